@@ -5,6 +5,8 @@ package uuid
  * Time: 10:08 AM
  ***************/
 
+import "fmt"
+
 const (
 	variantIndex = 8
 	versionIndex = 6
@@ -43,11 +45,28 @@ func (o *Array) Bytes() []byte {
 }
 
 func (o Array) String() string {
-	return formatter(&o, format)
+	var b [maxFormatLength]byte
+	return string(currentFormat().append(b[:0], o[:]))
+}
+
+// AppendString appends the UUID printed in the default format to dst
+// and returns the extended buffer. It does not allocate when dst has
+// enough capacity and the format only uses %x and %X verbs.
+func (o Array) AppendString(dst []byte) []byte {
+	return currentFormat().append(dst, o[:])
+}
+
+// Format implements fmt.Formatter. See FormatVerb.
+func (o Array) Format(pFmt fmt.State, pVerb rune) {
+	FormatVerb(&o, pFmt, pVerb)
 }
 
-func (o Array) Format(pFormat string) string {
-	return formatter(&o, pFormat)
+// FormatString prints the UUID in the format pattern. It replaces the
+// Format(string) string method, whose name fmt.Formatter now uses.
+//
+// Deprecated: Use Formatter.
+func (o Array) FormatString(pFormat string) string {
+	return formatter(&o, compiledFormat(pFormat))
 }
 
 // Set the three most significant bits (bits 0, 1 and 2) of the
@@ -29,3 +29,16 @@ func TestUUID_Array_UnmarshalBinary(t *testing.T) {
 		t.Errorf("Expected bytes")
 	}
 }
+
+func TestUUID_Array_FormatString(t *testing.T) {
+	u := new(Array)
+	u.Unmarshal(array_bytes)
+	for _, f := range []Format{CurlyHyphen, "urn:uuid:%x-%x-%x-%x%x-%x"} {
+		if s := u.FormatString(string(f)); s != Formatter(u, f) {
+			t.Errorf("Expected FormatString to print as Formatter with %s but got %s", f, s)
+		}
+	}
+	if s := u.FormatString(string(CurlyHyphen)); s != "{aacfee12-d400-2723-00d3-23124a1189ff}" {
+		t.Error("Expected the UUID in the format but got", s)
+	}
+}
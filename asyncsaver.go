@@ -0,0 +1,261 @@
+package uuid
+
+/****************
+ * Date: 19/10/26
+ * Time: 8:40 PM
+ ***************/
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// The schedule of an AsyncSaver created without one
+const asyncSaveSchedule = time.Second
+
+var errSaverClosed = errors.New("uuid.AsyncSaver: saver is closed")
+
+// **************************************************** AsyncSaver
+
+// An AsyncSaver wraps a StateSaver and saves from a background
+// goroutine so that v1 generation does not wait for the store.
+//
+// Following RFC 4122 §4.2.1.2 it saves a high-water mark ahead of the
+// clock, the time now plus twice the schedule, as the HighWater of
+// each Snapshot. Generation only blocks when a timestamp reaches the mark
+// before the next save has moved it on, for example at startup. After
+// a restart the clock sequence is incremented while the clock is
+// behind the mark, so no timestamp is repeated with the same sequence.
+//
+// The wrapped saver should save on every call, as the AsyncSaver keeps
+// the schedule. Close, or the package Shutdown, saves the latest state
+// a final time.
+type AsyncSaver struct {
+	saver    StateSaver
+	schedule time.Duration
+
+	sync.Mutex
+	pending   Snapshot
+	dirty     bool
+	highWater Timestamp
+	err       error
+	started   bool
+	closed    bool
+
+	// closed and replaced after each save
+	saved chan struct{}
+
+	wake    chan struct{}
+	stop    chan struct{}
+	stopped chan struct{}
+
+	// serialises the saves
+	write sync.Mutex
+}
+
+// NewAsyncSaver creates an AsyncSaver which saves to pSaver every
+// pSchedule, or every second when pSchedule is not positive.
+func NewAsyncSaver(pSaver StateSaver, pSchedule time.Duration) *AsyncSaver {
+	if pSchedule <= 0 {
+		pSchedule = asyncSaveSchedule
+	}
+	return &AsyncSaver{
+		saver:    pSaver,
+		schedule: pSchedule,
+		saved:    make(chan struct{}),
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// Init initialises the wrapped saver and starts saving in the
+// background. Calling it again does not start another goroutine.
+func (o *AsyncSaver) Init(pCtx context.Context) (Snapshot, error) {
+	snapshot, err := o.saver.Init(pCtx)
+	o.Lock()
+	o.highWater = snapshot.highWater
+	if o.highWater < snapshot.past {
+		o.highWater = snapshot.past
+	}
+	start := !o.started && !o.closed
+	o.started = true
+	o.Unlock()
+	if start {
+		go o.run()
+	}
+	return snapshot, err
+}
+
+// Save keeps the snapshot for the next background save
+// It blocks until the high-water mark is saved past the snapshot's
+// timestamp, or the context is done.
+func (o *AsyncSaver) Save(pCtx context.Context, pSnapshot Snapshot) error {
+	o.Lock()
+	defer o.Unlock()
+	o.pending, o.dirty = pSnapshot, true
+	for pSnapshot.past >= o.highWater {
+		if o.closed {
+			return errSaverClosed
+		}
+		saved := o.saved
+		o.Unlock()
+		select {
+		case o.wake <- struct{}{}:
+		default:
+		}
+		select {
+		case <-saved:
+		case <-pCtx.Done():
+			o.Lock()
+			return pCtx.Err()
+		}
+		o.Lock()
+		if o.err != nil {
+			return o.err
+		}
+	}
+	return nil
+}
+
+// Flush saves the latest snapshot now
+// It returns the context's error when the context is done first; the
+// save then carries on in the background.
+func (o *AsyncSaver) Flush(pCtx context.Context) error {
+	return within(pCtx, func() error {
+		return o.save(pCtx, true)
+	})
+}
+
+// Close stops the background goroutine, saves the latest snapshot and
+// closes the wrapped saver. A saver which was never initialised only
+// closes the wrapped saver.
+func (o *AsyncSaver) Close() error {
+	return o.close(context.Background())
+}
+
+// Closes the saver as Close does but returns the context's error when
+// the context is done first, leaving the final save and close to
+// finish in the background
+func (o *AsyncSaver) close(pCtx context.Context) error {
+	o.Lock()
+	if o.closed {
+		o.Unlock()
+		return nil
+	}
+	o.closed = true
+	started := o.started
+	o.Unlock()
+	if !started {
+		return o.saver.Close()
+	}
+	close(o.stop)
+	return within(pCtx, func() error {
+		<-o.stopped
+		err := o.save(pCtx, true)
+		if cErr := o.saver.Close(); err == nil {
+			err = cErr
+		}
+		return err
+	})
+}
+
+func (o *AsyncSaver) run() {
+	ticker := time.NewTicker(o.schedule)
+	defer ticker.Stop()
+	defer close(o.stopped)
+	for {
+		select {
+		case <-o.stop:
+			return
+		case <-ticker.C:
+		case <-o.wake:
+		}
+		o.save(context.Background(), false)
+	}
+}
+
+// Saves the pending snapshot if it changed since the last save
+// A final save keeps the high-water mark, otherwise it is moved ahead
+// of the clock.
+func (o *AsyncSaver) save(pCtx context.Context, pFinal bool) error {
+	o.write.Lock()
+	defer o.write.Unlock()
+
+	o.Lock()
+	if !o.dirty {
+		o.Unlock()
+		return nil
+	}
+	snapshot := o.pending
+	high := o.highWater
+	if !pFinal {
+		// a tick is 100 nano seconds
+		high = timestamp() + Timestamp(2*o.schedule/100)
+	}
+	if high <= snapshot.past {
+		high = snapshot.past + 1
+	}
+	o.dirty = false
+	o.Unlock()
+
+	snapshot.highWater = high
+	err := o.saver.Save(pCtx, snapshot)
+
+	o.Lock()
+	o.err = err
+	if err != nil {
+		o.dirty = true
+	} else if high > o.highWater {
+		o.highWater = high
+	}
+	close(o.saved)
+	o.saved = make(chan struct{})
+	o.Unlock()
+	return err
+}
+
+// Runs the function and returns its error, or the context's error if
+// the context is done first. The function then runs on unwaited, so a
+// store which ignores the context cannot hold up the caller.
+func within(pCtx context.Context, pFunc func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- pFunc()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-pCtx.Done():
+		return pCtx.Err()
+	}
+}
+
+// **************************************************** Shutdown
+
+// Shutdown saves the state a final time and closes the package
+// StateSaver. Call it before the process exits; v1 UUIDs made
+// afterwards are not saved.
+// The context bounds the final save and close of an AsyncSaver: when
+// it is done Shutdown returns its error and leaves them to finish in
+// the background.
+func Shutdown(pCtx context.Context) error {
+	state.Lock()
+	defer state.Unlock()
+	if state.saver == nil {
+		return nil
+	}
+	var err error
+	if saver, ok := state.saver.(*AsyncSaver); ok {
+		err = saver.Flush(pCtx)
+		if cErr := saver.close(pCtx); err == nil {
+			err = cErr
+		}
+	} else {
+		err = state.saver.Close()
+	}
+	state.saver = nil
+	return err
+}
@@ -0,0 +1,219 @@
+package uuid
+
+/****************
+ * Date: 19/10/26
+ * Time: 9:25 PM
+ ***************/
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Counts the saves which reach the store
+type countingSaver struct {
+	InMemorySaver
+	saves int
+}
+
+func (o *countingSaver) Save(pCtx context.Context, pSnapshot Snapshot) error {
+	o.Lock()
+	o.saves++
+	o.Unlock()
+	return o.InMemorySaver.Save(pCtx, pSnapshot)
+}
+
+func (o *countingSaver) last() (Snapshot, int) {
+	o.Lock()
+	defer o.Unlock()
+	return o.saved, o.saves
+}
+
+// The timestamp of a v1 UUID
+func timestampOf(pUUID UUID) Timestamp {
+	o := pUUID.(*Struct)
+	return Timestamp(o.timeLow) | Timestamp(o.timeMid)<<32 | Timestamp(o.timeHiAndVersion&0x0FFF)<<48
+}
+
+func TestUUID_AsyncSaver(t *testing.T) {
+	ctx := context.Background()
+	inner := new(countingSaver)
+	saver := NewAsyncSaver(inner, time.Hour)
+	if _, err := saver.Init(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the first save waits for the high-water mark
+	now := timestamp()
+	if err := saver.Save(ctx, NewSnapshot(now, state_bytes[:6], 1)); err != nil {
+		t.Fatal(err)
+	}
+	saved, saves := inner.last()
+	if saves != 1 || saved.HighWater() <= now || saved.Past() != now || saved.Sequence() != 1 {
+		t.Fatalf("Expected a high-water mark past %d to be saved but got %d %d after %d saves", now, saved.HighWater(), saved.Past(), saves)
+	}
+
+	// later saves below the mark return without saving
+	for i := 0; i < 100; i++ {
+		if err := saver.Save(ctx, NewSnapshot(now+Timestamp(i), state_bytes[:6], 2)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, saves := inner.last(); saves != 1 {
+		t.Errorf("Expected no saves below the high-water mark but got %d", saves-1)
+	}
+
+	// the final save keeps the mark and the latest state
+	high := saved.HighWater()
+	if err := saver.Close(); err != nil {
+		t.Fatal(err)
+	}
+	saved, saves = inner.last()
+	if saves != 2 || saved.HighWater() != high || saved.Past() != now+99 || saved.Sequence() != 2 {
+		t.Errorf("Expected the latest state to be saved on Close but got %d %d %d after %d saves", saved.HighWater(), saved.Past(), saved.Sequence(), saves)
+	}
+	if err := saver.Save(ctx, NewSnapshot(high, state_bytes[:6], 3)); err != errSaverClosed {
+		t.Error("Expected a closed saver not to save past the high-water mark but got:", err)
+	}
+}
+
+// Tests that no v1 UUID is made past the saved high-water mark
+func TestUUID_AsyncSaver_highWater(t *testing.T) {
+	defer SetupFileSystemStateSaver(config)
+
+	inner := new(countingSaver)
+	if err := SetupCustomStateSaver(NewAsyncSaver(inner, 5*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for end := time.Now().Add(100 * time.Millisecond); time.Now().Before(end); {
+				now := timestampOf(NewV1())
+				if saved, _ := inner.last(); saved.HighWater() <= now {
+					t.Errorf("Expected %d to be before the saved high-water mark %d", now, saved.HighWater())
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if _, saves := inner.last(); saves < 2 {
+		t.Errorf("Expected the high-water mark to be moved on in the background but got %d saves", saves)
+	}
+	if err := Shutdown(context.Background()); err != nil {
+		t.Error("Expected Shutdown to succeed but got:", err)
+	}
+	if state.saver != nil {
+		t.Error("Expected Shutdown to remove the saver")
+	}
+}
+
+func TestUUID_SetupFileSystemStateSaver_Async(t *testing.T) {
+	defer SetupFileSystemStateSaver(config)
+
+	path := filepath.Join(t.TempDir(), "state.unique")
+	if err := SetupFileSystemStateSaver(StateSaverConfig{Path: path, SaveSchedule: time.Hour, Async: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := state.saver.(*AsyncSaver); !ok {
+		t.Fatalf("Expected an AsyncSaver but got %T", state.saver)
+	}
+	now := timestampOf(NewV1())
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := NewFileSystemSaver(StateSaverConfig{Path: path}).Init(context.Background())
+	if err != nil || snapshot.HighWater() <= now || snapshot.Past() != now {
+		t.Errorf("Expected %d and a high-water mark past it to be saved but got %d %d %v", now, snapshot.Past(), snapshot.HighWater(), err)
+	}
+
+	// a restart bumps the sequence but the mark is not a regression
+	observer := new(countingObserver)
+	SetupClockObserver(observer)
+	SetupClockPolicy(ClockFail)
+	defer func() {
+		SetupClockPolicy(ClockBump)
+		SetupClockObserver(nil)
+	}()
+	if err := SetupFileSystemStateSaver(StateSaverConfig{Path: path, SaveSchedule: time.Hour, Async: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := TryNewV1(); err != nil || len(observer.regressions) != 0 {
+		t.Errorf("Expected no regression after a restart but got %v %v", err, observer.regressions)
+	}
+	if observer.bumps == 0 {
+		t.Errorf("Expected the sequence to be bumped behind the high-water mark but got %d bumps", observer.bumps)
+	}
+	Shutdown(context.Background())
+}
+
+func TestUUID_AsyncSaver_Close(t *testing.T) {
+	ctx := context.Background()
+
+	// never initialised
+	done := make(chan error, 1)
+	go func() { done <- NewAsyncSaver(new(countingSaver), time.Hour).Close() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error("Expected Close to succeed but got:", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Close of a saver which never started to return")
+	}
+
+	// initialised twice
+	saver := NewAsyncSaver(new(countingSaver), time.Hour)
+	for i := 0; i < 2; i++ {
+		if _, err := saver.Init(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := saver.Close(); err != nil {
+		t.Error("Expected Close to succeed but got:", err)
+	}
+	if err := saver.Close(); err != nil {
+		t.Error("Expected a second Close to do nothing but got:", err)
+	}
+}
+
+// A saver whose saves block until released, ignoring the context
+type blockingSaver struct {
+	InMemorySaver
+	release chan struct{}
+}
+
+func (o *blockingSaver) Save(pCtx context.Context, pSnapshot Snapshot) error {
+	<-o.release
+	return o.InMemorySaver.Save(pCtx, pSnapshot)
+}
+
+func TestUUID_Shutdown_deadline(t *testing.T) {
+	defer SetupFileSystemStateSaver(config)
+
+	inner := &blockingSaver{release: make(chan struct{})}
+	defer close(inner.release)
+	saver := NewAsyncSaver(inner, time.Hour)
+	if err := SetupCustomStateSaver(saver); err != nil {
+		t.Fatal(err)
+	}
+	saver.Lock()
+	saver.pending, saver.dirty = NewSnapshot(timestamp(), state_bytes[:6], 1), true
+	saver.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Error("Expected the deadline to be exceeded but got:", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Expected Shutdown to return at its deadline but it took %v", d)
+	}
+}
@@ -0,0 +1,126 @@
+package uuid
+
+/****************
+ * Date: 20/10/26
+ * Time: 11:30 AM
+ ***************/
+
+import (
+	"crypto/rand"
+)
+
+// The Fill functions create many UUIDs in one call for bulk jobs.
+// They fill a slice the caller provides, share one allocation for the
+// UUIDs of a batch and pay for locking and reading entropy once rather
+// than for every UUID. The Batch functions allocate the slice and
+// panic where the Fill functions return an error.
+
+// FillV4 fills dst with RFC4122 version 4 UUIDs from a single read of
+// random data.
+// Returns an error if random data cannot be read.
+func FillV4(dst []UUID) error {
+	arrays := make([]Array, len(dst))
+	b := make([]byte, len(dst)*length)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	for i := range arrays {
+		o := &arrays[i]
+		copy(o[:], b[i*length:])
+		o.setRFC4122Variant()
+		o.setVersion(4)
+		dst[i] = o
+	}
+	return nil
+}
+
+// FillV7 fills dst with RFC9562 version 7 UUIDs in order.
+// The random bits are read once for the batch and incremented.
+// Returns an error if random data cannot be read.
+func FillV7(dst []UUID) error {
+	return v7Generator.Fill(dst)
+}
+
+// FillV1 fills dst with RFC4122 version 1 UUIDs. The node is read and
+// the state saved once for the batch. The state stays locked except
+// while the generator waits for the clock, when other v1 and v6
+// callers may take timestamps in the middle of the batch.
+// The timestamps increase unless the clock moves backwards during the
+// batch: under ClockBump the UUIDs after the jump carry on from the
+// earlier reading with a new clock sequence, so they are unique but
+// not in order.
+// Returns a *ClockError if the clock moved backwards and the ClockFail
+// policy is set up; dst then holds the UUIDs made before the error.
+func FillV1(dst []UUID) error {
+	structs := make([]Struct, len(dst))
+	return fillTimeBased(dst, func(i int, pNow Timestamp) UUID {
+		return formatV1Into(&structs[i], pNow, state.sequence, uint16(1), ReservedRFC4122, state.node)
+	})
+}
+
+// FillV6 fills dst with RFC9562 version 6 UUIDs as FillV1 does for
+// version 1; they are in order unless the clock moves backwards.
+func FillV6(dst []UUID) error {
+	arrays := make([]Array, len(dst))
+	return fillTimeBased(dst, func(i int, pNow Timestamp) UUID {
+		return formatV6Into(&arrays[i], pNow, state.sequence, state.node)
+	})
+}
+
+// Fills dst with the state locked, formatting each UUID from the
+// timestamp and the state
+func fillTimeBased(dst []UUID, pFormat func(int, Timestamp) UUID) error {
+	if len(dst) == 0 {
+		return nil
+	}
+	state.Lock()
+	defer state.Unlock()
+	node := currentUUIDNodeId()
+	for i := range dst {
+		now, err := state.clock()
+		if err != nil {
+			if i > 0 {
+				state.persist()
+			}
+			return err
+		}
+		state.read(now, node)
+		dst[i] = pFormat(i, now)
+	}
+	state.persist()
+	return nil
+}
+
+// NewV4Batch will generate n RFC4122 version 4 UUIDs as FillV4 does.
+// It panics if random data cannot be read.
+func NewV4Batch(n int) []UUID {
+	return newBatch(n, FillV4)
+}
+
+// NewV7Batch will generate n RFC9562 version 7 UUIDs as FillV7 does.
+// It panics if random data cannot be read.
+func NewV7Batch(n int) []UUID {
+	return newBatch(n, FillV7)
+}
+
+// NewV1Batch will generate n RFC4122 version 1 UUIDs as FillV1 does.
+// It panics with a *ClockError if the clock moved backwards and the
+// ClockFail policy is set up.
+func NewV1Batch(n int) []UUID {
+	return newBatch(n, FillV1)
+}
+
+// NewV6Batch will generate n RFC9562 version 6 UUIDs as FillV6 does.
+// It panics with a *ClockError if the clock moved backwards and the
+// ClockFail policy is set up.
+func NewV6Batch(n int) []UUID {
+	return newBatch(n, FillV6)
+}
+
+func newBatch(n int, pFill func([]UUID) error) []UUID {
+	ids := make([]UUID, n)
+	if err := pFill(ids); err != nil {
+		panic(err)
+	}
+	return ids
+}
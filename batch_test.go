@@ -0,0 +1,179 @@
+package uuid
+
+/****************
+ * Date: 20/10/26
+ * Time: 11:55 AM
+ ***************/
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+const batchSize = 10000
+
+func TestUUID_NewBatch(t *testing.T) {
+	for _, v := range []struct {
+		version int
+		batch   func(int) []UUID
+	}{
+		{4, NewV4Batch},
+		{7, NewV7Batch},
+		{1, NewV1Batch},
+		{6, NewV6Batch},
+	} {
+		if ids := v.batch(0); len(ids) != 0 {
+			t.Errorf("Expected an empty batch but got %d", len(ids))
+		}
+		ids := v.batch(batchSize)
+		if len(ids) != batchSize {
+			t.Fatalf("Expected %d UUIDs but got %d", batchSize, len(ids))
+		}
+		seen := make(map[string]bool, batchSize)
+		for _, u := range ids {
+			if u.Version() != v.version || u.Variant() != ReservedRFC4122 {
+				t.Fatalf("Expected version %d RFC4122 UUIDs but got %s", v.version, u)
+			}
+			if seen[u.String()] {
+				t.Fatalf("Expected unique UUIDs but %s was repeated", u)
+			}
+			seen[u.String()] = true
+		}
+	}
+}
+
+func TestUUID_Fill(t *testing.T) {
+	for _, fill := range []func([]UUID) error{FillV4, FillV7, FillV1, FillV6} {
+		if err := fill(nil); err != nil {
+			t.Error("Expected an empty slice to be filled but got:", err)
+		}
+		dst := make([]UUID, 100)
+		if err := fill(dst[10:90]); err != nil {
+			t.Fatal(err)
+		}
+		for i, u := range dst {
+			if (u == nil) != (i < 10 || i >= 90) {
+				t.Fatalf("Expected only the given slice to be filled but %d is %v", i, u)
+			}
+		}
+	}
+
+	// the node is read once for a batch
+	dst := make([]UUID, 100)
+	if err := FillV1(dst); err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range dst {
+		if !bytes.Equal(u.Bytes()[10:], dst[0].Bytes()[10:]) {
+			t.Fatalf("Expected one node for the batch but got %s and %s", dst[0], u)
+		}
+	}
+}
+
+func TestUUID_FillV1_ClockFail(t *testing.T) {
+	SetupClockPolicy(ClockFail)
+	defer func() {
+		SetupClockPolicy(ClockBump)
+		setPastAhead(0)
+	}()
+	for _, fill := range []func([]UUID) error{FillV1, FillV6} {
+		setPastAhead(10 * time.Second)
+		dst := make([]UUID, 10)
+		if _, ok := fill(dst).(*ClockError); !ok || dst[0] != nil {
+			t.Error("Expected a ClockError and no UUIDs")
+		}
+	}
+	func() {
+		defer func() {
+			if _, ok := recover().(*ClockError); !ok {
+				t.Error("Expected NewV1Batch to panic with a ClockError")
+			}
+		}()
+		NewV1Batch(10)
+	}()
+}
+
+// Tests a clock which moves backwards in the middle of a batch
+func TestUUID_FillV1_regression(t *testing.T) {
+	observer := new(countingObserver)
+	SetupClockObserver(observer)
+	defer func() {
+		SetupClockObserver(nil)
+		setPastAhead(0)
+	}()
+	setPastAhead(0)
+
+	const jump = 50
+	structs := make([]Struct, batchSize)
+	dst := make([]UUID, batchSize)
+	err := fillTimeBased(dst, func(i int, pNow Timestamp) UUID {
+		if i == jump {
+			// the clock read a second ahead here; the state is locked
+			pNow += ticksPerSecond
+			state.past = pNow
+		}
+		return formatV1Into(&structs[i], pNow, state.sequence, uint16(1), ReservedRFC4122, state.node)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(observer.regressions) != 1 {
+		t.Fatalf("Expected one regression but got %v", observer.regressions)
+	}
+	if timestampOf(dst[jump+1]) >= timestampOf(dst[jump]) {
+		t.Error("Expected the timestamps to go back after the jump")
+	}
+	seen := make(map[string]bool)
+	for i, u := range dst {
+		if seen[u.String()] {
+			t.Fatalf("Expected unique UUIDs but %s at %d was repeated", u, i)
+		}
+		seen[u.String()] = true
+	}
+	before, after := dst[jump].Bytes()[8:10], dst[jump+1].Bytes()[8:10]
+	if bytes.Equal(before, after) {
+		t.Error("Expected a new clock sequence after the jump")
+	}
+}
+
+func TestUUID_NewBatch_ordered(t *testing.T) {
+	ids := NewV7Batch(batchSize)
+	for i := 1; i < len(ids); i++ {
+		if bytes.Compare(ids[i-1].Bytes(), ids[i].Bytes()) >= 0 {
+			t.Fatalf("Expected v7 UUIDs in order but %s came before %s", ids[i-1], ids[i])
+		}
+	}
+	ids = NewV1Batch(batchSize)
+	for i := 1; i < len(ids); i++ {
+		if timestampOf(ids[i-1]) >= timestampOf(ids[i]) {
+			t.Fatalf("Expected v1 timestamps in order but %s came before %s", ids[i-1], ids[i])
+		}
+	}
+	ids = NewV6Batch(batchSize)
+	for i := 1; i < len(ids); i++ {
+		if bytes.Compare(ids[i-1].Bytes()[:8], ids[i].Bytes()[:8]) >= 0 {
+			t.Fatalf("Expected v6 UUIDs in order but %s came before %s", ids[i-1], ids[i])
+		}
+	}
+}
+
+func BenchmarkUUID_FillV4(b *testing.B) {
+	benchmarkFill(b, FillV4)
+}
+
+func BenchmarkUUID_FillV7(b *testing.B) {
+	benchmarkFill(b, FillV7)
+}
+
+func BenchmarkUUID_FillV1(b *testing.B) {
+	benchmarkFill(b, FillV1)
+}
+
+func benchmarkFill(b *testing.B, pFill func([]UUID) error) {
+	dst := make([]UUID, 1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i += len(dst) {
+		pFill(dst)
+	}
+}
@@ -0,0 +1,99 @@
+package uuid
+
+/****************
+ * Date: 20/10/26
+ * Time: 9:15 AM
+ ***************/
+
+import (
+	"time"
+)
+
+// The longest backward jump ClockWait waits out
+const maxClockWait = time.Second
+
+// ClockPolicy decides what v1 and v6 generation does when the clock
+// reads earlier than the last UUID, for example after an NTP step.
+type ClockPolicy int
+
+const (
+	// ClockBump increments the clock sequence of the UUID made at
+	// the earlier reading, whether or not the node has changed, and
+	// carries on from that reading. The bumped sequence is kept until
+	// the clock passes the last UUID before the jump. This is the
+	// default.
+	ClockBump ClockPolicy = iota
+
+	// ClockWait waits until the clock is past the last UUID. Jumps
+	// of more than a second are bumped instead, as waiting would stall
+	// every v1 caller.
+	ClockWait
+
+	// ClockFail fails with a *ClockError. TryNewV1 and TryNewV6 return
+	// the error; NewV1 and NewV6 panic.
+	ClockFail
+)
+
+// ClockError reports that the clock moved backwards
+type ClockError struct {
+	// How far the clock is behind the last UUID
+	Jump time.Duration
+}
+
+func (o *ClockError) Error() string {
+	return "uuid: clock moved backwards by " + o.Jump.String()
+}
+
+// A ClockObserver is told about events of the v1 and v6 clock so that
+// they can be counted and alerted on. The methods are called while
+// the generator is locked and must return quickly.
+type ClockObserver interface {
+	// ClockRegression is called when the clock reads earlier than
+	// the last UUID
+	ClockRegression(pJump time.Duration)
+
+	// SequenceBump is called when the clock sequence is incremented
+	SequenceBump()
+
+	// ClockSpin is called when the generator sleeps as UUIDs were
+	// made faster than the clock ticks
+	ClockSpin()
+}
+
+// SetupClockPolicy sets the ClockPolicy of v1 and v6 generation
+func SetupClockPolicy(pPolicy ClockPolicy) {
+	state.Lock()
+	defer state.Unlock()
+	state.policy = pPolicy
+}
+
+// SetupClockObserver sets the ClockObserver; nil removes it
+func SetupClockObserver(pObserver ClockObserver) {
+	state.Lock()
+	defer state.Unlock()
+	state.observer = pObserver
+}
+
+// Reads the clock for a v1 or v6 UUID and applies the policy when the
+// clock is behind the last UUID
+func (o *State) clock() (Timestamp, error) {
+	now := o.currentUUIDTimestamp()
+	if now > o.past {
+		return now, nil
+	}
+	// a tick is 100 nano seconds
+	jump := time.Duration(o.past-now) * 100
+	if o.observer != nil {
+		o.observer.ClockRegression(jump)
+	}
+	switch {
+	case o.policy == ClockFail:
+		return 0, &ClockError{Jump: jump}
+	case o.policy == ClockWait && jump <= maxClockWait:
+		for now <= o.past {
+			o.sleep(time.Duration(o.past-now) * 100)
+			now = o.currentUUIDTimestamp()
+		}
+	}
+	return now, nil
+}
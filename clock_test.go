@@ -0,0 +1,159 @@
+package uuid
+
+/****************
+ * Date: 20/10/26
+ * Time: 9:50 AM
+ ***************/
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+type countingObserver struct {
+	sync.Mutex
+	regressions []time.Duration
+	bumps       int
+	spins       int
+}
+
+func (o *countingObserver) ClockRegression(pJump time.Duration) {
+	o.Lock()
+	defer o.Unlock()
+	o.regressions = append(o.regressions, pJump)
+}
+
+func (o *countingObserver) SequenceBump() {
+	o.Lock()
+	defer o.Unlock()
+	o.bumps++
+}
+
+func (o *countingObserver) ClockSpin() {
+	o.Lock()
+	defer o.Unlock()
+	o.spins++
+}
+
+// Moves the last UUID ahead of the clock and forgets earlier
+// regressions
+func setPastAhead(pAhead time.Duration) Timestamp {
+	state.Lock()
+	defer state.Unlock()
+	state.past = timestamp() + Timestamp(pAhead/100)
+	state.regressed = 0
+	return state.past
+}
+
+func TestUUID_SetupClockPolicy(t *testing.T) {
+	observer := new(countingObserver)
+	SetupClockObserver(observer)
+	defer func() {
+		SetupClockPolicy(ClockBump)
+		SetupClockObserver(nil)
+		setPastAhead(0)
+	}()
+
+	// the default bumps the sequence
+	setPastAhead(10 * time.Second)
+	NewV1()
+	if len(observer.regressions) != 1 || observer.regressions[0] < 9*time.Second || observer.regressions[0] > 10*time.Second {
+		t.Errorf("Expected a regression of 10s to be observed but got %v", observer.regressions)
+	}
+
+	SetupClockPolicy(ClockFail)
+	setPastAhead(10 * time.Second)
+	u, err := TryNewV1()
+	if e, ok := err.(*ClockError); !ok || u != nil || e.Jump < 9*time.Second {
+		t.Errorf("Expected a ClockError but got %v %v", u, err)
+	}
+	func() {
+		defer func() {
+			if _, ok := recover().(*ClockError); !ok {
+				t.Error("Expected NewV6 to panic with a ClockError")
+			}
+		}()
+		NewV6()
+	}()
+
+	SetupClockPolicy(ClockWait)
+	past := setPastAhead(50 * time.Millisecond)
+	now := time.Now()
+	if u := NewV1(); timestampOf(u) <= past || time.Since(now) < 40*time.Millisecond {
+		t.Errorf("Expected to wait for the clock to pass %d but got %d after %v", past, timestampOf(u), time.Since(now))
+	}
+
+	// too long to wait
+	setPastAhead(time.Hour)
+	now = time.Now()
+	if NewV1(); time.Since(now) > maxClockWait {
+		t.Errorf("Expected a large jump not to be waited out but took %v", time.Since(now))
+	}
+	if len(observer.regressions) != 5 {
+		t.Errorf("Expected every regression to be observed but got %v", observer.regressions)
+	}
+}
+
+func TestUUID_ClockObserver_SequenceBump(t *testing.T) {
+	observer := new(countingObserver)
+	s := new(State)
+	s.observer = observer
+	s.past = timestamp()
+	s.node = state_bytes
+	s.read(s.past-1, net.HardwareAddr(make([]byte, 6)))
+	s.restore(NewSnapshot(timestamp()+ticksPerSecond, state_bytes, 42))
+	if observer.bumps != 2 {
+		t.Errorf("Expected every sequence bump to be observed but got %d", observer.bumps)
+	}
+}
+
+func TestUUID_SetupClockPolicy_pinnedNode(t *testing.T) {
+	if err := SetupNodeID([]byte{0x03, 0x00, 0x00, 0x00, 0x00, 0x01}); err != nil {
+		t.Fatal(err)
+	}
+	observer := new(countingObserver)
+	SetupClockObserver(observer)
+	defer func() {
+		SetupClockObserver(nil)
+		SetupNodeInterface("")
+		setPastAhead(0)
+	}()
+
+	NewV1()
+	state.Lock()
+	sequence := state.sequence
+	state.Unlock()
+	setPastAhead(5 * time.Second)
+	NewV1()
+	state.Lock()
+	defer state.Unlock()
+	if len(observer.regressions) != 1 || observer.bumps != 1 {
+		t.Errorf("Expected one regression and one bump but got %v and %d", observer.regressions, observer.bumps)
+	}
+	if state.sequence != (sequence+1)&sequenceMask {
+		t.Errorf("Expected the sequence to go from %d up by one but got %d", sequence, state.sequence)
+	}
+
+	// the clock is still within the time it went back over
+	state.Unlock()
+	NewV1()
+	state.Lock()
+	if observer.bumps != 1 || state.sequence != (sequence+1)&sequenceMask {
+		t.Errorf("Expected the bumped sequence %d to be kept but got %d", (sequence+1)&sequenceMask, state.sequence)
+	}
+}
+
+func TestUUID_ClockObserver_noRegression(t *testing.T) {
+	observer := new(countingObserver)
+	SetupClockObserver(observer)
+	defer SetupClockObserver(nil)
+
+	for i := 0; i < 100000; i++ {
+		NewV1()
+	}
+	if len(observer.regressions) != 0 {
+		t.Errorf("Expected no regressions from a steady clock but got %d", len(observer.regressions))
+	}
+}
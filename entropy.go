@@ -0,0 +1,83 @@
+package uuid
+
+/****************
+ * Date: 20/10/26
+ * Time: 1:20 PM
+ ***************/
+
+import (
+	"crypto/rand"
+	"sync"
+	"sync/atomic"
+)
+
+// A good size for SetupEntropyPool
+const DefaultEntropyPoolSize = 4096
+
+var (
+	// The size of the entropy buffers, 0 when the pool is off
+	entropySize int32
+
+	entropyBuffers = sync.Pool{
+		New: func() interface{} {
+			return new(entropyBuffer)
+		},
+	}
+)
+
+// ******************************************************  Entropy
+
+// A buffer of random data
+// Bytes are zeroed as they are handed out so that a copy of the
+// process memory does not reveal UUIDs already made.
+type entropyBuffer struct {
+	data   []byte
+	offset int
+}
+
+// SetupEntropyPool makes NewV4 draw its random bits from buffers of
+// pSize bytes which are refilled from crypto/rand, instead of reading
+// crypto/rand for every UUID. A size of 0 turns the pool off, which is
+// the default.
+//
+// The buffers are kept in a sync.Pool, which holds one per P, so that
+// goroutines do not contend for them. The data is as secure as
+// crypto/rand. It is also fork-safe: the Go runtime only forks to exec
+// another program, so no child process ever continues with a copy of
+// the buffers.
+func SetupEntropyPool(pSize int) {
+	if pSize < 0 {
+		pSize = 0
+	}
+	atomic.StoreInt32(&entropySize, int32(pSize))
+}
+
+// Fills p with random data from the pool, or straight from
+// crypto/rand if the pool is off or p is larger than a buffer
+func readEntropy(p []byte) {
+	size := int(atomic.LoadInt32(&entropySize))
+	if size < len(p) {
+		if _, err := rand.Read(p); err != nil {
+			panic(err)
+		}
+		return
+	}
+	o := entropyBuffers.Get().(*entropyBuffer)
+	if len(o.data) != size {
+		o.data = make([]byte, size)
+		o.offset = size
+	}
+	if o.offset+len(p) > size {
+		if _, err := rand.Read(o.data); err != nil {
+			panic(err)
+		}
+		o.offset = 0
+	}
+	used := o.data[o.offset : o.offset+len(p)]
+	copy(p, used)
+	for i := range used {
+		used[i] = 0
+	}
+	o.offset += len(p)
+	entropyBuffers.Put(o)
+}
@@ -0,0 +1,101 @@
+package uuid
+
+/****************
+ * Date: 18/10/26
+ * Time: 11:02 PM
+ ***************/
+
+import "fmt"
+
+const (
+	lowerHex = "0123456789abcdef"
+	upperHex = "0123456789ABCDEF"
+
+	// The length of the longest built in Format
+	maxFormatLength = 38
+)
+
+// The byte boundaries of the six printed groups
+var groups = [7]int{0, 4, 6, 8, 9, 10, length}
+
+// **********************************************  printFormat
+
+// A printFormat is a Format compiled for printing.
+// A pattern which uses only %x and %X verbs is printed by hex
+// encoding straight into the destination, which covers all the built
+// in formats. Any other pattern falls back to fmt.Sprintf.
+type printFormat struct {
+	pattern string
+
+	// The literal text before, between and after the six groups
+	text [7]string
+
+	// Whether each group is printed in upper case
+	upper [6]bool
+
+	// Whether the pattern can skip fmt.Sprintf
+	fast bool
+}
+
+// Compiles a format pattern which is known to hold 6 verbs
+func compileFormat(pFormat string) *printFormat {
+	o := &printFormat{pattern: pFormat}
+	last, g := 0, 0
+	for i := 0; i < len(pFormat); i++ {
+		if pFormat[i] != '%' {
+			continue
+		}
+		if g == len(o.upper) || i+1 == len(pFormat) {
+			return o
+		}
+		switch pFormat[i+1] {
+		case 'x':
+		case 'X':
+			o.upper[g] = true
+		default:
+			return o
+		}
+		o.text[g] = pFormat[last:i]
+		g++
+		i++
+		last = i + 1
+	}
+	if g != len(o.upper) {
+		return o
+	}
+	o.text[g] = pFormat[last:]
+	o.fast = true
+	return o
+}
+
+// Appends the 16 UUID bytes printed in the format to dst
+// Fewer bytes are printed as if padded with zeros.
+func (o *printFormat) append(dst []byte, pData []byte) []byte {
+	if len(pData) < length {
+		var b [length]byte
+		copy(b[:], pData)
+		pData = b[:]
+	}
+	if !o.fast {
+		return append(dst, o.sprintf(pData)...)
+	}
+	for i := range o.upper {
+		dst = append(dst, o.text[i]...)
+		digits := lowerHex
+		if o.upper[i] {
+			digits = upperHex
+		}
+		for _, b := range pData[groups[i]:groups[i+1]] {
+			dst = append(dst, digits[b>>4], digits[b&0x0F])
+		}
+	}
+	return append(dst, o.text[len(o.upper)]...)
+}
+
+// The slow path for patterns with other verbs
+// Copies the data so that the fast path callers do not escape
+func (o *printFormat) sprintf(pData []byte) string {
+	b := make([]byte, length)
+	copy(b, pData)
+	return fmt.Sprintf(o.pattern, b[0:4], b[4:6], b[6:8], b[8:9], b[9:10], b[10:length])
+}
@@ -0,0 +1,143 @@
+package uuid
+
+/****************
+ * Date: 18/10/26
+ * Time: 11:40 PM
+ ***************/
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var formats = []Format{
+	Clean, Curly, Bracket, CleanHyphen, CurlyHyphen, BracketHyphen, GoIdFormat,
+}
+
+func TestUUID_printFormat_append(t *testing.T) {
+	ids := []UUID{NewV4(), NewV1(), NamespaceDNS, New(uuid_bytes)}
+	patterns := []string{"urn:uuid:%x-%x-%x-%x%x-%x", "%v%v%v%v%v%v", "%x%x%x%x%x%", "%2x%x%x%x%x%x"}
+	for _, f := range formats {
+		patterns = append(patterns, string(f), strings.ToUpper(string(f)))
+	}
+	for _, p := range patterns {
+		f := compileFormat(p)
+		for _, u := range ids {
+			b := u.Bytes()
+			expected := fmt.Sprintf(p, b[0:4], b[4:6], b[6:8], b[8:9], b[9:10], b[10:16])
+			if s := string(f.append(nil, b)); s != expected {
+				t.Errorf("Expected %s to print %s but got %s", p, expected, s)
+			}
+		}
+	}
+	if compileFormat("%v%x%x%x%x%x").fast || compileFormat("%x%x%x%x%x%").fast {
+		t.Error("Expected patterns with other verbs to use the slow path")
+	}
+
+	// short data is padded with zeros
+	for p, expected := range map[string]string{
+		string(CleanHyphen): "01020000-0000-0000-0000-000000000000",
+		"%v-%x-%x-%x%x-%x":  "[1 2 0 0]-0000-0000-0000-000000000000",
+	} {
+		if s := string(compileFormat(p).append(nil, []byte{0x01, 0x02})); s != expected {
+			t.Errorf("Expected short data to print as %s in %s but got %s", expected, p, s)
+		}
+	}
+}
+
+func TestUUID_AppendString(t *testing.T) {
+	defer SwitchFormat(CleanHyphen)
+
+	ids := []UUID{NewV4(), NewV1()}
+	for _, f := range formats {
+		SwitchFormat(f)
+		for _, u := range ids {
+			var s []byte
+			switch u := u.(type) {
+			case *Array:
+				s = u.AppendString([]byte("id="))
+			case *Struct:
+				s = u.AppendString([]byte("id="))
+			}
+			if string(s) != "id="+Formatter(u, f) {
+				t.Errorf("Expected AppendString to match the %s format but got %s", f, s)
+			}
+		}
+	}
+}
+
+func TestUUID_AppendString_allocs(t *testing.T) {
+	defer SwitchFormat(CleanHyphen)
+
+	a := NewV4().(*Array)
+	s := NewV1().(*Struct)
+	buf := make([]byte, 0, maxFormatLength)
+	for _, f := range formats {
+		SwitchFormat(f)
+		if n := testing.AllocsPerRun(100, func() { a.AppendString(buf) }); n != 0 {
+			t.Errorf("Expected Array.AppendString not to allocate with %s but got %v", f, n)
+		}
+		if n := testing.AllocsPerRun(100, func() { s.AppendString(buf) }); n != 0 {
+			t.Errorf("Expected Struct.AppendString not to allocate with %s but got %v", f, n)
+		}
+		if n := testing.AllocsPerRun(100, func() { _ = a.String() }); n > 1 {
+			t.Errorf("Expected Array.String to allocate once with %s but got %v", f, n)
+		}
+	}
+}
+
+func TestUUID_Formatter_allocs(t *testing.T) {
+	u := NewV4()
+	for _, f := range formats {
+		if compiledFormat(string(f)) != compiledFormat(string(f)) {
+			t.Errorf("Expected the %s format to be compiled once", f)
+		}
+		if upper := strings.ToUpper(string(f)); compiledFormat(upper) != compiledFormat(upper) {
+			t.Errorf("Expected the %s format to be compiled once", upper)
+		}
+		if n := testing.AllocsPerRun(100, func() { Formatter(u, f) }); n > 1 {
+			t.Errorf("Expected Formatter to allocate once with %s but got %v", f, n)
+		}
+	}
+	if p := "urn:uuid:%x-%x-%x-%x%x-%x"; compiledFormat(p) == compiledFormat(p) {
+		t.Error("Expected a pattern built at run time not to be kept")
+	}
+	if n := testing.AllocsPerRun(100, func() { FormatVerb(u, nopState{}, 'v') }); n > 1 {
+		t.Errorf("Expected FormatVerb not to compile a format but got %v allocations", n)
+	}
+}
+
+func BenchmarkUUID_String(b *testing.B) {
+	u := NewV4()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = u.String()
+	}
+}
+
+func BenchmarkUUID_AppendString(b *testing.B) {
+	u := NewV4().(*Array)
+	buf := make([]byte, 0, maxFormatLength)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = u.AppendString(buf[:0])
+	}
+}
+
+func BenchmarkUUID_Formatter(b *testing.B) {
+	u := NewV4()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Formatter(u, CurlyHyphen)
+	}
+}
+
+// A fmt.State which discards what is printed
+type nopState struct{}
+
+func (nopState) Write(pData []byte) (int, error)       { return len(pData), nil }
+func (nopState) WriteString(pData string) (int, error) { return len(pData), nil }
+func (nopState) Width() (int, bool)                    { return 0, false }
+func (nopState) Precision() (int, bool)                { return 0, false }
+func (nopState) Flag(int) bool                         { return false }
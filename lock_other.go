@@ -0,0 +1,21 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package uuid
+
+/****************
+ * Date: 19/10/26
+ * Time: 1:32 PM
+ ***************/
+
+import "os"
+
+// Opens the lock file
+// Platforms without flock get no inter-process locking
+func lockFile(pPath string, pMode os.FileMode) (*os.File, error) {
+	return os.OpenFile(pPath, os.O_RDWR|os.O_CREATE, pMode)
+}
+
+func unlockFile(pFile *os.File) {
+	pFile.Close()
+}
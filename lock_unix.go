@@ -0,0 +1,39 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package uuid
+
+/****************
+ * Date: 19/10/26
+ * Time: 1:32 PM
+ ***************/
+
+import (
+	"os"
+	"syscall"
+)
+
+// Opens the lock file and blocks until an exclusive flock is held
+func lockFile(pPath string, pMode os.FileMode) (*os.File, error) {
+	f, err := os.OpenFile(pPath, os.O_RDWR|os.O_CREATE, pMode)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+		if err != syscall.EINTR {
+			break
+		}
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// Releases the lock; closing the file drops the flock
+func unlockFile(pFile *os.File) {
+	syscall.Flock(int(pFile.Fd()), syscall.LOCK_UN)
+	pFile.Close()
+}
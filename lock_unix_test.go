@@ -0,0 +1,44 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package uuid
+
+/****************
+ * Date: 19/10/26
+ * Time: 1:58 PM
+ ***************/
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUUID_lockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.unique.lock")
+	f, err := lockFile(path, 0644)
+	if err != nil {
+		t.Fatal("Expected the lock to be taken:", err)
+	}
+	locked := make(chan struct{})
+	go func() {
+		f2, err := lockFile(path, 0644)
+		if err != nil {
+			t.Error("Expected the lock to be taken:", err)
+		} else {
+			unlockFile(f2)
+		}
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		t.Fatal("Expected the second lock to wait for the first")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlockFile(f)
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the second lock to be taken after the first was released")
+	}
+}
@@ -0,0 +1,52 @@
+package uuid
+
+/****************
+ * Date: 19/10/26
+ * Time: 6:05 PM
+ ***************/
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// This implements the StateSaver interface in memory
+//
+// The state only lives as long as the saver, so it does not protect
+// v1 UUIDs across a restart of the process. It is meant for tests
+// and for processes which hand a saver from one generator to the
+// next: calling Init again with the same saver restores what was
+// saved last.
+type InMemorySaver struct {
+	// Save every x nanoseconds
+	SaveSchedule time.Duration
+
+	sync.Mutex
+	saved Snapshot
+	next  Timestamp
+}
+
+// Saves the current state of the generator
+// If the scheduled save is reached then the snapshot is kept
+func (o *InMemorySaver) Save(pCtx context.Context, pSnapshot Snapshot) error {
+	o.Lock()
+	defer o.Unlock()
+	if pSnapshot.past >= o.next {
+		o.saved = pSnapshot
+		// a tick is 100 nano seconds
+		o.next = pSnapshot.past + Timestamp(o.SaveSchedule/100)
+	}
+	return nil
+}
+
+func (o *InMemorySaver) Init(pCtx context.Context) (Snapshot, error) {
+	o.Lock()
+	defer o.Unlock()
+	o.next = o.saved.past
+	return o.saved, nil
+}
+
+func (o *InMemorySaver) Close() error {
+	return nil
+}
@@ -0,0 +1,93 @@
+package uuid
+
+/****************
+ * Date: 20/10/26
+ * Time: 5:20 PM
+ ***************/
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"hash"
+	"io"
+)
+
+// ******************************************************  NameHasher
+
+// A NameHasher creates a name-based UUID from a name which is written
+// in pieces rather than held as a string, such as the contents of a
+// file:
+//
+//	h := NewV5Hasher(NamespaceURL)
+//	if _, err := io.Copy(h, file); err != nil {
+//		return err
+//	}
+//	id := h.UUID()
+//
+// The UUID is the same as NewV3, NewV5, NewV8Hash or NewV8HMAC would
+// create from the whole name. A NameHasher is not safe for concurrent
+// use.
+type NameHasher struct {
+	hash    hash.Hash
+	version int
+}
+
+// NewV3Hasher creates a NameHasher for version 3 UUIDs in the namespace.
+func NewV3Hasher(pNs UUID) *NameHasher {
+	return newNameHasher(pNs, md5.New(), 3)
+}
+
+// NewV5Hasher creates a NameHasher for version 5 UUIDs in the namespace.
+func NewV5Hasher(pNs UUID) *NameHasher {
+	return newNameHasher(pNs, sha1.New(), 5)
+}
+
+// NewV8Hasher creates a NameHasher for version 8 UUIDs in the namespace
+// using the hash as NewV8Hash does.
+// It panics if the hash is shorter than 128 bits.
+func NewV8Hasher(pNs UUID, pHash func() hash.Hash) *NameHasher {
+	h := pHash()
+	if h.Size() < length {
+		panic("uuid.NewV8Hasher: hash is shorter than 128 bits")
+	}
+	return newNameHasher(pNs, h, 8)
+}
+
+// NewV8HMACHasher creates a NameHasher for version 8 UUIDs in the
+// namespace keyed as NewV8HMAC does.
+// It panics if the key is empty.
+func NewV8HMACHasher(pKey []byte, pNs UUID) *NameHasher {
+	return NewV8Hasher(pNs, hmacSHA256(pKey, "uuid.NewV8HMACHasher"))
+}
+
+func newNameHasher(pNs UUID, pHash hash.Hash, pVersion int) *NameHasher {
+	// Hash writer never returns an error
+	pHash.Write(pNs.Bytes())
+	return &NameHasher{hash: pHash, version: pVersion}
+}
+
+// Write adds the bytes to the name. It never returns an error.
+func (o *NameHasher) Write(pName []byte) (int, error) {
+	return o.hash.Write(pName)
+}
+
+// WriteString adds the string to the name. It never returns an error.
+func (o *NameHasher) WriteString(pName string) (int, error) {
+	return io.WriteString(o.hash, pName)
+}
+
+// ReadFrom adds everything read from the reader to the name until EOF.
+// Returns the number of bytes read and any error other than EOF.
+func (o *NameHasher) ReadFrom(pReader io.Reader) (int64, error) {
+	return io.Copy(o.hash, pReader)
+}
+
+// UUID creates the UUID from the name written so far. More can be
+// written to the name afterwards.
+func (o *NameHasher) UUID() UUID {
+	u := new(Array)
+	u.Unmarshal(o.hash.Sum(nil)[:length])
+	u.setRFC4122Variant()
+	u.setVersion(o.version)
+	return u
+}
@@ -0,0 +1,70 @@
+package uuid
+
+/****************
+ * Date: 20/10/26
+ * Time: 5:45 PM
+ ***************/
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestUUID_NameHasher(t *testing.T) {
+	for _, v := range []struct {
+		hasher   func() *NameHasher
+		expected UUID
+	}{
+		{func() *NameHasher { return NewV3Hasher(NamespaceURL) }, NewV3(NamespaceURL, goLang)},
+		{func() *NameHasher { return NewV5Hasher(NamespaceURL) }, NewV5(NamespaceURL, goLang)},
+		{func() *NameHasher { return NewV8Hasher(NamespaceURL, sha256.New) }, NewV8Hash(NamespaceURL, goLang, sha256.New)},
+	} {
+		h := v.hasher()
+		h.Write([]byte(goLang[:5]))
+		h.WriteString(string(goLang[5:10]))
+		if n, err := h.ReadFrom(strings.NewReader(string(goLang[10:]))); err != nil || n != int64(len(goLang)-10) {
+			t.Errorf("Expected ReadFrom to read the rest of the name but got %d %v", n, err)
+		}
+		if u := h.UUID(); !Equal(u, v.expected) {
+			t.Errorf("Expected a name written in pieces to give %s but got %s", v.expected, u)
+		}
+		if u := h.UUID(); !Equal(u, v.expected) {
+			t.Errorf("Expected UUID to be repeatable but got %s", u)
+		}
+		h.WriteString("more")
+		if u := h.UUID(); Equal(u, v.expected) {
+			t.Error("Expected a longer name to give a different UUID")
+		}
+	}
+}
+
+func TestUUID_NameHasher_stream(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 1<<16)
+	h := NewV5Hasher(NamespaceOID)
+	if _, err := io.Copy(h, bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if u := h.UUID(); !Equal(u, NewV5(NamespaceOID, Name(data))) {
+		t.Errorf("Expected a streamed name to match NewV5 but got %s", u)
+	}
+	if _, err := NewV5Hasher(NamespaceOID).ReadFrom(iotest.ErrReader(io.ErrUnexpectedEOF)); err != io.ErrUnexpectedEOF {
+		t.Errorf("Expected the read error to be returned but got %v", err)
+	}
+}
+
+func TestUUID_NewV5Bytes(t *testing.T) {
+	name := []byte{0x00, 0xff, 0x10, 0x80}
+	if u := NewV5Bytes(NamespaceDNS, name); !Equal(u, NewV5(NamespaceDNS, Name(name))) {
+		t.Errorf("Expected NewV5Bytes to match NewV5 but got %s", u)
+	}
+	if u := NewV3Bytes(NamespaceDNS, name); !Equal(u, NewV3(NamespaceDNS, Name(name))) {
+		t.Errorf("Expected NewV3Bytes to match NewV3 but got %s", u)
+	}
+	if u := NewV5Bytes(NamespaceDNS, name); u.Version() != 5 || u.Variant() != ReservedRFC4122 {
+		t.Errorf("Expected a version 5 RFC4122 UUID but got %s", u)
+	}
+}
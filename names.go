@@ -0,0 +1,430 @@
+package uuid
+
+/****************
+ * Date: 20/10/26
+ * Time: 6:30 PM
+ ***************/
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// The ports which are removed from a URLName
+var defaultPorts = map[string]string{
+	"ftp":   "21",
+	"http":  "80",
+	"https": "443",
+	"ws":    "80",
+	"wss":   "443",
+}
+
+// The attribute types of an X500Name which are written by name
+var x500Attributes = map[string]string{
+	"2.5.4.3":                    "cn",
+	"2.5.4.6":                    "c",
+	"2.5.4.7":                    "l",
+	"2.5.4.8":                    "st",
+	"2.5.4.9":                    "street",
+	"2.5.4.10":                   "o",
+	"2.5.4.11":                   "ou",
+	"0.9.2342.19200300.100.1.1":  "uid",
+	"0.9.2342.19200300.100.1.25": "dc",
+}
+
+// The names below implement UniqueName in a canonical form so that
+// different spellings of the same resource create the same UUID:
+//
+//	dns, _ := NewDNSName("Example.COM.")
+//	NewV5(NamespaceDNS, dns) // the same as for "example.com"
+//
+// Each is created with its New function which returns an error if
+// the name is invalid.
+
+// *******************************************************  DNSName
+
+// A DNSName is a fully qualified domain name for NamespaceDNS.
+type DNSName struct {
+	name string
+}
+
+// NewDNSName creates a DNSName in lower case without a trailing dot.
+// Labels may hold letters, digits, hyphens and underscores; names
+// outside ASCII must be given in their punycode form.
+func NewDNSName(pName string) (DNSName, error) {
+	name := strings.ToLower(strings.TrimSuffix(pName, "."))
+	if name == "" || len(name) > 253 {
+		return DNSName{}, errors.New("uuid.NewDNSName: invalid length")
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 {
+			return DNSName{}, errors.New("uuid.NewDNSName: invalid label length")
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return DNSName{}, errors.New("uuid.NewDNSName: label begins or ends with a hyphen")
+		}
+		for i := 0; i < len(label); i++ {
+			if c := label[i]; !isAlphaNum(c) && c != '-' && c != '_' {
+				return DNSName{}, errors.New("uuid.NewDNSName: invalid character")
+			}
+		}
+	}
+	return DNSName{name}, nil
+}
+
+// Returns the canonical name. Satisfies the Stringer interface.
+func (o DNSName) String() string {
+	return o.name
+}
+
+// *******************************************************  URLName
+
+// A URLName is an absolute URL for NamespaceURL.
+type URLName struct {
+	name string
+}
+
+// NewURLName creates a URLName normalised as described in RFC3986
+// section 6.2.2 and for common schemes section 6.2.3:
+//
+//	the scheme and host are lower case
+//	percent-encodings are upper case and unreserved characters
+//	are decoded
+//	dot segments are removed from the path
+//	a default port, an empty query and an empty path after the
+//	host are removed, a path of / is kept
+//
+// HTTP://Example.COM:80/a/./b/../%7euser?  becomes
+// http://example.com/a/~user
+func NewURLName(pName string) (URLName, error) {
+	u, err := url.Parse(pName)
+	if err != nil {
+		return URLName{}, errors.New("uuid.NewURLName: " + err.Error())
+	}
+	if !u.IsAbs() {
+		return URLName{}, errors.New("uuid.NewURLName: URL is not absolute")
+	}
+	b := new(strings.Builder)
+	b.WriteString(strings.ToLower(u.Scheme))
+	b.WriteByte(':')
+	if u.Opaque != "" {
+		b.WriteString(normalisePercent(u.Opaque))
+	} else {
+		if strings.HasPrefix(pName[len(u.Scheme)+1:], "//") {
+			b.WriteString("//")
+			if u.User != nil {
+				b.WriteString(normalisePercent(u.User.String()))
+				b.WriteByte('@')
+			}
+			host := strings.ToLower(u.Host)
+			if port := u.Port(); port != "" && port == defaultPorts[u.Scheme] {
+				host = strings.TrimSuffix(host, ":"+port)
+			}
+			b.WriteString(normalisePercent(host))
+		}
+		path := removeDotSegments(normalisePercent(u.EscapedPath()))
+		if path == "" && u.Host != "" {
+			path = "/"
+		}
+		b.WriteString(path)
+	}
+	if u.RawQuery != "" {
+		b.WriteByte('?')
+		b.WriteString(normalisePercent(u.RawQuery))
+	}
+	if f := u.EscapedFragment(); f != "" {
+		b.WriteByte('#')
+		b.WriteString(normalisePercent(f))
+	}
+	return URLName{b.String()}, nil
+}
+
+// Returns the canonical name. Satisfies the Stringer interface.
+func (o URLName) String() string {
+	return o.name
+}
+
+// *******************************************************  OIDName
+
+// An OIDName is an ISO object identifier for NamespaceOID.
+type OIDName struct {
+	name string
+}
+
+// NewOIDName creates an OIDName from the dotted decimal form of an
+// object identifier such as 1.3.6.1.4.1.343, which may be given as a
+// urn:oid: URN. Arcs must not have leading zeros, the first arc must
+// be 0, 1 or 2 and below 0 and 1 the second arc must be at most 39.
+func NewOIDName(pName string) (OIDName, error) {
+	name := pName
+	if len(name) > 8 && strings.EqualFold(name[:8], "urn:oid:") {
+		name = name[8:]
+	}
+	arcs := strings.Split(name, ".")
+	if len(arcs) < 2 {
+		return OIDName{}, errors.New("uuid.NewOIDName: an OID must have at least two arcs")
+	}
+	for _, arc := range arcs {
+		if arc == "" || (len(arc) > 1 && arc[0] == '0') {
+			return OIDName{}, errors.New("uuid.NewOIDName: invalid arc " + arc)
+		}
+		for i := 0; i < len(arc); i++ {
+			if arc[i] < '0' || arc[i] > '9' {
+				return OIDName{}, errors.New("uuid.NewOIDName: invalid arc " + arc)
+			}
+		}
+	}
+	switch {
+	case len(arcs[0]) > 1 || arcs[0][0] > '2':
+		return OIDName{}, errors.New("uuid.NewOIDName: the first arc must be 0, 1 or 2")
+	case arcs[0] != "2" && (len(arcs[1]) > 2 || (len(arcs[1]) == 2 && arcs[1] > "39")):
+		return OIDName{}, errors.New("uuid.NewOIDName: the second arc must be at most 39")
+	}
+	return OIDName{name}, nil
+}
+
+// Returns the canonical name. Satisfies the Stringer interface.
+func (o OIDName) String() string {
+	return o.name
+}
+
+// ******************************************************  X500Name
+
+// An X500Name is an X.500 distinguished name for NamespaceX500.
+type X500Name struct {
+	name string
+}
+
+// NewX500Name creates an X500Name from the string form of a
+// distinguished name described in RFC4514 such as
+// CN=Steve Kille,O=Isode Limited,C=GB.
+//
+// In the canonical form:
+//
+//	attribute types are lower case and well known OIDs such as
+//	2.5.4.3 are written by name
+//	values are lower case with spaces trimmed and runs of spaces
+//	replaced by one space, and are escaped as in RFC4514
+//	the attributes of a multi-valued RDN are sorted
+//	RDNs are separated by a comma without spaces
+//
+// Quoted values and semicolon separators from RFC1779 are accepted.
+func NewX500Name(pName string) (X500Name, error) {
+	p := &dnParser{s: pName}
+	var rdns []string
+	for {
+		var rdn []string
+		for {
+			ava, err := p.attribute()
+			if err != nil {
+				return X500Name{}, err
+			}
+			rdn = append(rdn, ava)
+			if !p.consume('+') {
+				break
+			}
+		}
+		sort.Strings(rdn)
+		rdns = append(rdns, strings.Join(rdn, "+"))
+		if p.done() {
+			break
+		}
+		if !p.consume(',') && !p.consume(';') {
+			return X500Name{}, errors.New("uuid.NewX500Name: expected , between RDNs")
+		}
+	}
+	return X500Name{strings.Join(rdns, ",")}, nil
+}
+
+// Returns the canonical name. Satisfies the Stringer interface.
+func (o X500Name) String() string {
+	return o.name
+}
+
+// Reads a distinguished name
+type dnParser struct {
+	s string
+	i int
+}
+
+func (o *dnParser) skipSpaces() {
+	for o.i < len(o.s) && o.s[o.i] == ' ' {
+		o.i++
+	}
+}
+
+func (o *dnParser) done() bool {
+	o.skipSpaces()
+	return o.i == len(o.s)
+}
+
+func (o *dnParser) consume(c byte) bool {
+	o.skipSpaces()
+	if o.i < len(o.s) && o.s[o.i] == c {
+		o.i++
+		return true
+	}
+	return false
+}
+
+// Reads type=value and returns it in canonical form
+func (o *dnParser) attribute() (string, error) {
+	o.skipSpaces()
+	start := o.i
+	for o.i < len(o.s) && (isAlphaNum(o.s[o.i]) || o.s[o.i] == '-' || o.s[o.i] == '.') {
+		o.i++
+	}
+	kind := strings.ToLower(o.s[start:o.i])
+	kind = strings.TrimPrefix(kind, "oid.")
+	if kind == "" || !o.consume('=') {
+		return "", errors.New("uuid.NewX500Name: expected type=value")
+	}
+	if name, ok := x500Attributes[kind]; ok {
+		kind = name
+	}
+	value, err := o.value()
+	if err != nil {
+		return "", err
+	}
+	return kind + "=" + value, nil
+}
+
+// Reads a value and returns it in canonical form
+func (o *dnParser) value() (string, error) {
+	o.skipSpaces()
+	if o.i < len(o.s) && o.s[o.i] == '#' {
+		start := o.i
+		for o.i < len(o.s) && !strings.ContainsRune(",;+ ", rune(o.s[o.i])) {
+			o.i++
+		}
+		return strings.ToLower(o.s[start:o.i]), nil
+	}
+	var value []byte
+	quoted := o.i < len(o.s) && o.s[o.i] == '"'
+	if quoted {
+		o.i++
+	}
+	for o.i < len(o.s) {
+		c := o.s[o.i]
+		if quoted && c == '"' {
+			break
+		}
+		if !quoted && strings.IndexByte(",;+", c) >= 0 {
+			break
+		}
+		if !quoted && strings.IndexByte("\"<>", c) >= 0 {
+			return "", errors.New("uuid.NewX500Name: unescaped " + string(c))
+		}
+		if c == '\\' {
+			o.i++
+			rest := o.s[o.i:]
+			if len(rest) >= 2 {
+				if hi, ok := fromHex(rest[0]); ok {
+					if lo, ok := fromHex(rest[1]); ok {
+						value = append(value, hi<<4|lo)
+						o.i += 2
+						continue
+					}
+				}
+			}
+			if rest == "" || strings.IndexByte(" #\"+,;<=>\\", rest[0]) < 0 {
+				return "", errors.New("uuid.NewX500Name: invalid escape")
+			}
+			c = rest[0]
+		}
+		value = append(value, c)
+		o.i++
+	}
+	if quoted && !o.consume('"') {
+		return "", errors.New("uuid.NewX500Name: unterminated quote")
+	}
+	v := strings.ToLower(strings.Join(strings.Fields(string(value)), " "))
+	if v == "" {
+		return "", errors.New("uuid.NewX500Name: empty value")
+	}
+	return escapeDNValue(v), nil
+}
+
+// Escapes a value as described in RFC4514 section 2.4
+func escapeDNValue(pValue string) string {
+	b := new(strings.Builder)
+	for i := 0; i < len(pValue); i++ {
+		c := pValue[i]
+		if strings.IndexByte("\"+,;<>\\=", c) >= 0 || (i == 0 && c == '#') {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// ***************************************************  Helpers
+
+func isAlphaNum(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// Upper cases percent-encodings and decodes those of unreserved
+// characters
+func normalisePercent(pValue string) string {
+	if strings.IndexByte(pValue, '%') < 0 {
+		return pValue
+	}
+	b := new(strings.Builder)
+	for i := 0; i < len(pValue); i++ {
+		c := pValue[i]
+		if c == '%' && i+2 < len(pValue) {
+			hi, ok1 := fromHex(pValue[i+1])
+			lo, ok2 := fromHex(pValue[i+2])
+			if ok1 && ok2 {
+				d := hi<<4 | lo
+				if isAlphaNum(d) || strings.IndexByte("-._~", d) >= 0 {
+					b.WriteByte(d)
+				} else {
+					b.WriteByte('%')
+					b.WriteByte(upperHex[hi])
+					b.WriteByte(upperHex[lo])
+				}
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// Removes . and .. segments from a path as described in RFC3986
+// section 5.2.4
+func removeDotSegments(pPath string) string {
+	if !strings.Contains(pPath, ".") {
+		return pPath
+	}
+	var out []string
+	segments := strings.Split(pPath, "/")
+	for i, s := range segments {
+		last := i == len(segments)-1
+		switch s {
+		case ".":
+			if last {
+				out = append(out, "")
+			}
+		case "..":
+			if len(out) > 1 || (len(out) == 1 && out[0] != "") {
+				out = out[:len(out)-1]
+			}
+			if last {
+				out = append(out, "")
+			}
+		default:
+			out = append(out, s)
+		}
+	}
+	path := strings.Join(out, "/")
+	if strings.HasPrefix(pPath, "/") && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path
+}
@@ -0,0 +1,104 @@
+package uuid
+
+/****************
+ * Date: 20/10/26
+ * Time: 7:40 PM
+ ***************/
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUUID_NewDNSName(t *testing.T) {
+	for _, v := range []string{"example.com", "Example.COM.", "EXAMPLE.com"} {
+		n, err := NewDNSName(v)
+		if err != nil || n.String() != "example.com" {
+			t.Errorf("Expected %s to give example.com but got %s %v", v, n, err)
+		}
+		if !Equal(NewV5(NamespaceDNS, n), NewV5(NamespaceDNS, Name("example.com"))) {
+			t.Errorf("Expected %s to give the same UUID as example.com", v)
+		}
+	}
+	for _, v := range []string{
+		"", ".", "example..com", "-example.com", "example-.com", "exa mple.com", "exämple.com",
+		strings.Repeat("a", 64) + ".com", strings.Repeat("abcdefg.", 32) + "com",
+	} {
+		if _, err := NewDNSName(v); err == nil {
+			t.Error("Expected error due to invalid DNS name:", v)
+		}
+	}
+}
+
+func TestUUID_NewURLName(t *testing.T) {
+	for _, v := range []struct {
+		name, expected string
+	}{
+		{"HTTP://Example.COM:80/a/./b/../%7euser?", "http://example.com/a/~user"},
+		{"http://example.com", "http://example.com/"},
+		{"https://example.com:443/", "https://example.com/"},
+		{"https://example.com:8443/", "https://example.com:8443/"},
+		{"http://example.com/a%2fb/%c3%a9?q=%3d#F%2d", "http://example.com/a%2Fb/%C3%A9?q=%3D#F-"},
+		{"http://example.com/a/b/../../../c/.", "http://example.com/c/"},
+		{"http://User@example.com/a/..", "http://User@example.com/"},
+		{"urn:ISBN:0-486-%7e27557-4", "urn:ISBN:0-486-~27557-4"},
+		{"file:///etc/./hosts", "file:///etc/hosts"},
+	} {
+		n, err := NewURLName(v.name)
+		if err != nil || n.String() != v.expected {
+			t.Errorf("Expected %s to give %s but got %s %v", v.name, v.expected, n, err)
+		}
+	}
+	for _, v := range []string{"", "example.com", "/a/b", "http://example.com:port/", "http://[::1"} {
+		if _, err := NewURLName(v); err == nil {
+			t.Error("Expected error due to invalid URL:", v)
+		}
+	}
+}
+
+func TestUUID_NewOIDName(t *testing.T) {
+	for _, v := range []struct {
+		name, expected string
+	}{
+		{"1.3.6.1.4.1.343", "1.3.6.1.4.1.343"},
+		{"urn:oid:2.999.1", "2.999.1"},
+		{"URN:OID:0.39", "0.39"},
+	} {
+		n, err := NewOIDName(v.name)
+		if err != nil || n.String() != v.expected {
+			t.Errorf("Expected %s to give %s but got %s %v", v.name, v.expected, n, err)
+		}
+	}
+	for _, v := range []string{"", "1", "1.", ".1.3", "1..3", "1.03", "3.1", "10.1", "1.40", "0.100", "1.3.a", "1.3.-1", "urn:oid:"} {
+		if _, err := NewOIDName(v); err == nil {
+			t.Error("Expected error due to invalid OID:", v)
+		}
+	}
+}
+
+func TestUUID_NewX500Name(t *testing.T) {
+	for _, v := range []struct {
+		name, expected string
+	}{
+		{"CN=Steve Kille,O=Isode Limited,C=GB", "cn=steve kille,o=isode limited,c=gb"},
+		{" cn = Steve   Kille ; o=\"Isode Limited\" , 2.5.4.6=gb", "cn=steve kille,o=isode limited,c=gb"},
+		{"OU=Sales+CN=J. Smith,DC=example,DC=net", "cn=j. smith+ou=sales,dc=example,dc=net"},
+		{"CN=Lu\\C4\\8Di\\C4\\87", "cn=lučić"},
+		{"CN=James \\\"Jim\\\" Smith\\, III,OID.0.9.2342.19200300.100.1.25=net", "cn=james \\\"jim\\\" smith\\, iii,dc=net"},
+		{"1.3.6.1.4.1.1466.0=#04024869", "1.3.6.1.4.1.1466.0=#04024869"},
+		{"CN=\\#Hash", "cn=\\#hash"},
+		{"CN=a=b", "cn=a\\=b"},
+		{"CN=a\\=b", "cn=a\\=b"},
+		{"CN=a O=b", "cn=a o\\=b"},
+	} {
+		n, err := NewX500Name(v.name)
+		if err != nil || n.String() != v.expected {
+			t.Errorf("Expected %s to give %s but got %s %v", v.name, v.expected, n, err)
+		}
+	}
+	for _, v := range []string{"", "CN", "CN=", "=x", "CN=a,", "CN=a\\", "CN=a\\x", "CN=\"a", "CN=a<b", "CN=a>b"} {
+		if _, err := NewX500Name(v); err == nil {
+			t.Error("Expected error due to invalid DN:", v)
+		}
+	}
+}
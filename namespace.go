@@ -0,0 +1,90 @@
+package uuid
+
+/****************
+ * Date: 20/10/26
+ * Time: 8:15 PM
+ ***************/
+
+import (
+	"errors"
+	"sync"
+)
+
+// The standard namespaces as Namespace values. They are the same
+// UUIDs as NamespaceDNS, NamespaceURL, NamespaceOID and NamespaceX500,
+// which can also derive children with Child.
+var (
+	DNS  = NewNamespace(NamespaceDNS)
+	URL  = NewNamespace(NamespaceURL)
+	OID  = NewNamespace(NamespaceOID)
+	X500 = NewNamespace(NamespaceX500)
+)
+
+// The registered namespaces by name
+var namespaces = struct {
+	sync.RWMutex
+	byName map[string]*Namespace
+}{
+	byName: map[string]*Namespace{
+		"dns":  DNS,
+		"url":  URL,
+		"oid":  OID,
+		"x500": X500,
+	},
+}
+
+// *****************************************************  Namespace
+
+// A Namespace is a UUID used to create name-based UUIDs which can
+// derive child namespaces:
+//
+//	orders := NamespaceURL.Child("tenant-42").Child("orders")
+//	id := NewV5(orders, Name("1234"))
+//
+// Each level is the version 5 UUID of its name in the parent, so the
+// same path always gives the same namespace and, unlike a name made
+// with NewName, ("ab", "c") and ("a", "bc") give different ones.
+type Namespace struct {
+	Struct
+}
+
+// NewNamespace creates a Namespace from a copy of any UUID, so that
+// later changes to the UUID do not change the namespace.
+func NewNamespace(pUUID UUID) *Namespace {
+	o := &Namespace{Struct{size: length}}
+	o.Unmarshal(append([]byte(nil), pUUID.Bytes()...))
+	return o
+}
+
+// Child derives the namespace of the name within the UUID, such as
+// one of the standard namespaces or a Namespace.
+func (o *Struct) Child(pName string) *Namespace {
+	return NewNamespace(NewV5(o, Name(pName)))
+}
+
+// RegisterNamespace registers a namespace under a name so that it can
+// be found with LookupNamespace. The namespaces dns, url, oid and x500
+// are registered.
+// Returns an error if the name is already registered with a
+// different UUID.
+func RegisterNamespace(pName string, pUUID UUID) (*Namespace, error) {
+	namespaces.Lock()
+	defer namespaces.Unlock()
+	if o, ok := namespaces.byName[pName]; ok {
+		if !Equal(o, pUUID) {
+			return nil, errors.New("uuid.RegisterNamespace: " + pName + " is already registered")
+		}
+		return o, nil
+	}
+	o := NewNamespace(pUUID)
+	namespaces.byName[pName] = o
+	return o, nil
+}
+
+// LookupNamespace finds a namespace registered under the name.
+func LookupNamespace(pName string) (*Namespace, bool) {
+	namespaces.RLock()
+	defer namespaces.RUnlock()
+	o, ok := namespaces.byName[pName]
+	return o, ok
+}
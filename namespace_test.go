@@ -0,0 +1,93 @@
+package uuid
+
+/****************
+ * Date: 20/10/26
+ * Time: 8:40 PM
+ ***************/
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestUUID_Namespace_Child(t *testing.T) {
+	if s := fmt.Sprint(DNS); s != "6ba7b810-9dad-11d1-80b4-00c04fd430c8" {
+		t.Errorf("Expected a Namespace to print as a UUID but got %s", s)
+	}
+	for _, v := range []struct {
+		ns       *Namespace
+		expected *Struct
+	}{{DNS, NamespaceDNS}, {URL, NamespaceURL}, {OID, NamespaceOID}, {X500, NamespaceX500}} {
+		if !Equal(v.ns, v.expected) {
+			t.Errorf("Expected %s to be the standard namespace %s", v.ns, v.expected)
+		}
+	}
+	tenant := URL.Child("tenant-42")
+	if !Equal(tenant, NewV5(NamespaceURL, Name("tenant-42"))) {
+		t.Errorf("Expected a child to be the v5 UUID of its name but got %s", tenant)
+	}
+	orders := tenant.Child("orders")
+	if !Equal(orders, NewV5(NewV5(NamespaceURL, Name("tenant-42")), Name("orders"))) {
+		t.Errorf("Expected each level to be the v5 UUID of its parent but got %s", orders)
+	}
+	if !Equal(orders, URL.Child("tenant-42").Child("orders")) {
+		t.Error("Expected the same path to give the same namespace")
+	}
+	if orders.Version() != 5 || orders.Variant() != ReservedRFC4122 {
+		t.Errorf("Expected a version 5 RFC4122 namespace but got %s", orders)
+	}
+	if Equal(URL.Child("ab").Child("c"), URL.Child("a").Child("bc")) {
+		t.Error("Expected different paths to give different namespaces")
+	}
+	if Equal(URL.Child("tenant-42"), DNS.Child("tenant-42")) {
+		t.Error("Expected different parents to give different namespaces")
+	}
+	if !Equal(NamespaceURL.Child("tenant-42").Child("orders"), orders) {
+		t.Error("Expected the standard namespaces to derive the same children")
+	}
+}
+
+func TestUUID_NewNamespace_copy(t *testing.T) {
+	for _, u := range []UUID{NewV4(), NewV1()} {
+		b := append([]byte(nil), u.Bytes()...)
+		ns := NewNamespace(u)
+		switch u := u.(type) {
+		case *Array:
+			u[15] ^= 0xFF
+		case *Struct:
+			u.node[5] ^= 0xFF
+		}
+		if !bytes.Equal(ns.Bytes(), b) {
+			t.Errorf("Expected the namespace not to change with its UUID but got %s", ns)
+		}
+	}
+}
+
+func TestUUID_RegisterNamespace(t *testing.T) {
+	for _, v := range []string{"dns", "url", "oid", "x500"} {
+		if _, ok := LookupNamespace(v); !ok {
+			t.Error("Expected a standard namespace to be registered:", v)
+		}
+	}
+	if o, _ := LookupNamespace("url"); o != URL {
+		t.Error("Expected url to be URL")
+	}
+	if _, ok := LookupNamespace("test-tenant"); ok {
+		t.Error("Expected an unregistered namespace not to be found")
+	}
+	tenant := URL.Child("tenant-42")
+	o, err := RegisterNamespace("test-tenant", tenant)
+	if err != nil || !Equal(o, tenant) {
+		t.Fatalf("Expected the namespace to be registered but got %v %v", o, err)
+	}
+	if o2, err := RegisterNamespace("test-tenant", tenant); err != nil || o2 != o {
+		t.Errorf("Expected registering the same UUID again to succeed but got %v", err)
+	}
+	if _, err := RegisterNamespace("test-tenant", NamespaceDNS); err == nil {
+		t.Error("Expected error when a name is registered with a different UUID")
+	}
+	if o2, ok := LookupNamespace("test-tenant"); !ok || !Equal(o2.Child("orders"), tenant.Child("orders")) {
+		t.Error("Expected the registered namespace to be found")
+	}
+}
@@ -0,0 +1,141 @@
+package uuid
+
+/****************
+ * Date: 19/10/26
+ * Time: 10:10 PM
+ ***************/
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha1"
+	"errors"
+	"net"
+	"os"
+	"strings"
+)
+
+// The files which may hold the machine id, in order of preference
+var machineIdFiles = []string{"/etc/machine-id", "/var/lib/dbus/machine-id"}
+
+// RFC4122 §4.5 marks a node which is not an IEEE 802 address by
+// setting the multicast bit, the least significant bit of the first
+// octet, so that it cannot clash with a network card.
+const multicastBit = 0x01
+
+// ****************************************************  Node
+
+// The node id of v1 and v6 UUIDs is by default the first hardware
+// address which is up when a StateSaver is set up, and otherwise
+// random for each UUID. The SetupNode functions choose one node for
+// the process instead.
+
+// SetupNodeInterface uses the hardware address of the named network
+// interface. An empty name restores the default.
+// Returns an error if the interface does not have a 48 bit unicast
+// address.
+func SetupNodeInterface(pName string) error {
+	if pName == "" {
+		setupNode(nil)
+		return nil
+	}
+	inter, err := net.InterfaceByName(pName)
+	if err != nil {
+		return err
+	}
+	node := inter.HardwareAddr
+	if len(node) != 6 || bytes.Equal(node, make([]byte, 6)) {
+		return errors.New("uuid.SetupNodeInterface: " + pName + " has no 48 bit hardware address")
+	}
+	if node[0]&multicastBit != 0 {
+		return errors.New("uuid.SetupNodeInterface: " + pName + " has a multicast address")
+	}
+	setupNode(node)
+	return nil
+}
+
+// SetupNodeID uses an explicit 48 bit node id.
+// Unless it is the address of one of this host's network interfaces
+// the node must have the multicast bit set, as RFC4122 §4.5 requires
+// of a node which is not an IEEE 802 address.
+func SetupNodeID(pNode []byte) error {
+	if len(pNode) != 6 {
+		return errors.New("uuid.SetupNodeID: node id must be 48 bits")
+	}
+	if pNode[0]&multicastBit == 0 && !isLocalAddress(pNode) {
+		return errors.New("uuid.SetupNodeID: node id is not a local address and must have the multicast bit set")
+	}
+	setupNode(append(net.HardwareAddr(nil), pNode...))
+	return nil
+}
+
+// SetupNodeHash uses a node derived from a SHA-1 hash of the machine
+// id, or of the hostname if there is none, so that it is stable
+// across restarts without depending on the network.
+func SetupNodeHash() error {
+	var id string
+	for _, f := range machineIdFiles {
+		if data, err := os.ReadFile(f); err == nil {
+			if id = strings.TrimSpace(string(data)); id != "" {
+				break
+			}
+		}
+	}
+	if id == "" {
+		name, err := os.Hostname()
+		if err != nil {
+			return err
+		}
+		id = name
+	}
+	setupNode(hashNode(id))
+	return nil
+}
+
+// SetupNodeRandom uses one random node for the life of the process.
+func SetupNodeRandom() error {
+	node := make(net.HardwareAddr, 6)
+	if _, err := rand.Read(node); err != nil {
+		return err
+	}
+	node[0] |= multicastBit
+	setupNode(node)
+	return nil
+}
+
+func setupNode(pNode net.HardwareAddr) {
+	state.Lock()
+	defer state.Unlock()
+	state.setupNode = pNode
+	if pNode == nil {
+		// finds the hardware address again if there is a saver
+		state.randomNode = true
+		state.init()
+		return
+	}
+	state.node = pNode
+	state.randomNode = false
+}
+
+// Derives a node from a name with the multicast bit set
+func hashNode(pName string) net.HardwareAddr {
+	h := sha1.New()
+	h.Write([]byte("github.com/twinj/uuid/node:"))
+	h.Write([]byte(pName))
+	node := net.HardwareAddr(h.Sum(nil)[:6])
+	node[0] |= multicastBit
+	return node
+}
+
+func isLocalAddress(pNode []byte) bool {
+	intfcs, err := net.Interfaces()
+	if err != nil {
+		return false
+	}
+	for _, inter := range intfcs {
+		if bytes.Equal(inter.HardwareAddr, pNode) {
+			return true
+		}
+	}
+	return false
+}
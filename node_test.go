@@ -0,0 +1,85 @@
+package uuid
+
+/****************
+ * Date: 19/10/26
+ * Time: 10:40 PM
+ ***************/
+
+import (
+	"bytes"
+	"testing"
+)
+
+// The node of a v1 or v6 UUID
+func nodeOf(pUUID UUID) []byte {
+	return pUUID.Bytes()[10:]
+}
+
+func TestUUID_SetupNodeID(t *testing.T) {
+	defer SetupNodeInterface("")
+
+	for _, v := range [][]byte{
+		nil,
+		{0x01, 0x02, 0x03, 0x04, 0x05},
+		{0x02, 0x02, 0x03, 0x04, 0x05, 0x06},
+	} {
+		if err := SetupNodeID(v); err == nil {
+			t.Errorf("Expected error due to invalid node id %x", v)
+		}
+	}
+	node := []byte{0x03, 0x02, 0x03, 0x04, 0x05, 0x06}
+	if err := SetupNodeID(node); err != nil {
+		t.Fatal(err)
+	}
+	node[1] = 0
+	for _, u := range []UUID{NewV1(), NewV6(), NewV1()} {
+		if !bytes.Equal(nodeOf(u), []byte{0x03, 0x02, 0x03, 0x04, 0x05, 0x06}) {
+			t.Errorf("Expected the node id to be used but got %x", nodeOf(u))
+		}
+	}
+
+	SetupNodeInterface("")
+	if state.setupNode != nil {
+		t.Error("Expected the default node to be restored")
+	}
+}
+
+func TestUUID_SetupNodeHash(t *testing.T) {
+	defer SetupNodeInterface("")
+
+	if err := SetupNodeHash(); err != nil {
+		t.Fatal(err)
+	}
+	u := NewV1()
+	if err := SetupNodeHash(); err != nil {
+		t.Fatal(err)
+	}
+	if node := nodeOf(NewV1()); !bytes.Equal(node, nodeOf(u)) || node[0]&multicastBit == 0 {
+		t.Errorf("Expected a stable node with the multicast bit set but got %x and %x", nodeOf(u), node)
+	}
+	if bytes.Equal(hashNode("a"), hashNode("b")) {
+		t.Error("Expected different names to hash to different nodes")
+	}
+}
+
+func TestUUID_SetupNodeRandom(t *testing.T) {
+	defer SetupNodeInterface("")
+
+	if err := SetupNodeRandom(); err != nil {
+		t.Fatal(err)
+	}
+	u := NewV1()
+	if node := nodeOf(NewV6()); !bytes.Equal(node, nodeOf(u)) || node[0]&multicastBit == 0 {
+		t.Errorf("Expected one node with the multicast bit set but got %x and %x", nodeOf(u), node)
+	}
+}
+
+func TestUUID_SetupNodeInterface(t *testing.T) {
+	defer SetupNodeInterface("")
+
+	for _, v := range []string{"lo", "no-such-interface"} {
+		if err := SetupNodeInterface(v); err == nil {
+			t.Error("Expected error due to an interface without a hardware address:", v)
+		}
+	}
+}
@@ -6,10 +6,13 @@ package uuid
  ***************/
 
 import (
+	"crypto/hmac"
 	"crypto/md5"
 	"crypto/rand"
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/binary"
+	"hash"
 	"log"
 	seed "math/rand"
 	"net"
@@ -25,6 +28,9 @@ const (
 	// The result is the same for all other variants
 	// 0x80 and 0xA0 are used to identify RFC4122 compliance
 	variantGet = 0xE0
+
+	// The clock sequence is 14 bits
+	sequenceMask = 0x3FFF
 )
 
 var (
@@ -43,25 +49,69 @@ var (
 )
 
 func init() {
-	seed.Seed((int64(timestamp())^int64(gregorianToUNIXOffset))*0x6ba7b814<<0x6ba7b812 | 1391463463)
+	seed.Seed((int64(timestamp())^int64(gregorianToUNIXOffset))*0x6ba7b814 | 1391463463)
 	state = State{
 		randomNode:     true,
 		randomSequence: true,
 		past:           Timestamp((1391463463 * 10000000) + (100 * 10) + gregorianToUNIXOffset),
 		node:           nodeId,
-		sequence:       uint16(seed.Int()) & 0x3FFF,
+		sequence:       uint16(seed.Int()) & sequenceMask,
 		saver:          nil,
 	}
 }
 
 // NewV1 will generate a new RFC4122 version 1 UUID
+// It panics with a *ClockError if the clock moved backwards and the
+// ClockFail policy is set up.
 func NewV1() UUID {
+	u, err := TryNewV1()
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// TryNewV1 will generate a new RFC4122 version 1 UUID
+// Returns a *ClockError if the clock moved backwards and the
+// ClockFail policy is set up.
+func TryNewV1() (UUID, error) {
+	state.Lock()
+	defer state.Unlock()
+	now, err := state.clock()
+	if err != nil {
+		return nil, err
+	}
+	state.read(now, currentUUIDNodeId())
+	state.persist()
+	return formatV1(now, uint16(1), ReservedRFC4122, state.node), nil
+}
+
+// NewV6 will generate a new RFC9562 version 6 UUID
+// V6 holds the same timestamp, clock sequence and node as V1 but with
+// the most significant time bits first, so that it sorts by time.
+// It panics with a *ClockError if the clock moved backwards and the
+// ClockFail policy is set up.
+func NewV6() UUID {
+	u, err := TryNewV6()
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// TryNewV6 will generate a new RFC9562 version 6 UUID
+// Returns a *ClockError if the clock moved backwards and the
+// ClockFail policy is set up.
+func TryNewV6() (UUID, error) {
 	state.Lock()
 	defer state.Unlock()
-	now := currentUUIDTimestamp()
+	now, err := state.clock()
+	if err != nil {
+		return nil, err
+	}
 	state.read(now, currentUUIDNodeId())
 	state.persist()
-	return formatV1(now, uint16(1), ReservedRFC4122, state.node)
+	return formatV6(now, state.sequence, state.node), nil
 }
 
 // NewV3 will generate a new RFC4122 version 3 UUID
@@ -77,15 +127,21 @@ func NewV3(pNs UUID, pName UniqueName) UUID {
 	return o
 }
 
+// NewV3Bytes will generate a new RFC4122 version 3 UUID from a name
+// held as bytes, without converting it to a string.
+func NewV3Bytes(pNs UUID, pName []byte) UUID {
+	h := NewV3Hasher(pNs)
+	h.Write(pName)
+	return h.UUID()
+}
+
 // NewV4 will generate a new RFC4122 version 4 UUID
 // A cryptographically secure random UUID.
+// See SetupEntropyPool to amortise reads of random data.
 func NewV4() UUID {
 	o := new(Array)
 	// Read random values (or pseudo-randomly) into Array type.
-	_, err := rand.Read(o[:length])
-	if err != nil {
-		panic(err)
-	}
+	readEntropy(o[:length])
 	o.setRFC4122Variant()
 	o.setVersion(4)
 	return o
@@ -102,10 +158,62 @@ func NewV5(pNs UUID, pName UniqueName) UUID {
 	return o
 }
 
-// either generates a random node when there is an error or gets
-// the pre initialised one
+// NewV5Bytes will generate a new RFC4122 version 5 UUID from a name
+// held as bytes, without converting it to a string.
+// See NewV5Hasher for names which are read from a stream.
+func NewV5Bytes(pNs UUID, pName []byte) UUID {
+	h := NewV5Hasher(pNs)
+	h.Write(pName)
+	return h.UUID()
+}
+
+// NewV8Hash will generate a new RFC9562 version 8 name-based UUID
+// As described in RFC9562 Appendix B it is the first 128 bits of the
+// hash of a namespace identifier and a name, using any hash which is
+// at least 128 bits such as SHA-256, SHA-512 or SHA3:
+//
+//	NewV8Hash(NamespaceDNS, Name("www.example.com"), sha256.New)
+//
+// It panics if the hash is shorter than 128 bits.
+func NewV8Hash(pNs UUID, pName UniqueName, pHash func() hash.Hash) UUID {
+	h := pHash()
+	if h.Size() < length {
+		panic("uuid.NewV8Hash: hash is shorter than 128 bits")
+	}
+	o := new(Array)
+	Digest(o, pNs, pName, h)
+	o.setRFC4122Variant()
+	o.setVersion(8)
+	return o
+}
+
+// NewV8HMAC will generate a new RFC9562 version 8 name-based UUID
+// keyed with a secret, using HMAC-SHA256 as the hash of NewV8Hash.
+// Unlike NewV3 and NewV5 the UUID of a name cannot be computed or
+// guessed by anyone without the key. The key should be at least 32
+// random bytes.
+// It panics if the key is empty.
+func NewV8HMAC(pKey []byte, pNs UUID, pName UniqueName) UUID {
+	return NewV8Hash(pNs, pName, hmacSHA256(pKey, "uuid.NewV8HMAC"))
+}
+
+// Creates HMAC-SHA256 hashes with the key
+func hmacSHA256(pKey []byte, pCaller string) func() hash.Hash {
+	if len(pKey) == 0 {
+		panic(pCaller + ": empty key")
+	}
+	key := append([]byte(nil), pKey...)
+	return func() hash.Hash {
+		return hmac.New(sha256.New, key)
+	}
+}
+
+// either returns the node set up with a SetupNode function, generates
+// a random node when there is an error or gets the pre initialised one
 func currentUUIDNodeId() (node net.HardwareAddr) {
-	if state.randomNode {
+	if state.setupNode != nil {
+		node = state.setupNode
+	} else if state.randomNode {
 		b := make([]byte, 16+6)
 		_, err := rand.Read(b)
 		if err != nil {
@@ -132,15 +240,34 @@ func currentUUIDNodeId() (node net.HardwareAddr) {
 
 // Unmarshal data into struct for V1 UUIDs
 func formatV1(pNow Timestamp, pVersion uint16, pVariant byte, pNode []byte) UUID {
-	o := new(Struct)
+	return formatV1Into(new(Struct), pNow, state.sequence, pVersion, pVariant, pNode)
+}
+
+func formatV1Into(o *Struct, pNow Timestamp, pSequence uint16, pVersion uint16, pVariant byte, pNode []byte) UUID {
 	o.timeLow = uint32(pNow & 0xFFFFFFFF)
 	o.timeMid = uint16((pNow >> 32) & 0xFFFF)
 	o.timeHiAndVersion = uint16((pNow >> 48) & 0x0FFF)
 	o.timeHiAndVersion |= uint16(pVersion << 12)
-	o.sequenceLow = byte(state.sequence & 0xFF)
-	o.sequenceHiAndVariant = byte((state.sequence & 0x3F00) >> 8)
+	o.sequenceLow = byte(pSequence & 0xFF)
+	o.sequenceHiAndVariant = byte((pSequence & 0x3F00) >> 8)
 	o.sequenceHiAndVariant |= pVariant
 	o.node = pNode
 	o.size = length
 	return o
 }
+
+// Lays out the V1 fields with the time bits most significant first
+func formatV6(pNow Timestamp, pSequence uint16, pNode []byte) UUID {
+	return formatV6Into(new(Array), pNow, pSequence, pNode)
+}
+
+func formatV6Into(o *Array, pNow Timestamp, pSequence uint16, pNode []byte) UUID {
+	binary.BigEndian.PutUint32(o[0:4], uint32(pNow>>28))
+	binary.BigEndian.PutUint16(o[4:6], uint16(pNow>>12))
+	binary.BigEndian.PutUint16(o[6:8], uint16(pNow&0x0FFF))
+	binary.BigEndian.PutUint16(o[8:10], pSequence&sequenceMask)
+	copy(o[10:], pNode)
+	o.setRFC4122Variant()
+	o.setVersion(6)
+	return o
+}
@@ -6,9 +6,17 @@ package uuid
  ***************/
 
 import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
 	"fmt"
+	"hash"
+	"hash/crc32"
 	"net/url"
+	"strconv"
 	"testing"
+	"time"
 )
 
 var (
@@ -74,7 +82,7 @@ func TestUUID_NewV3(t *testing.T) {
 		u, u2, u3, u4,
 	}
 	for j, id := range ids {
-		i := NewV3(NamespaceURL, NewName(string(j), id))
+		i := NewV3(NamespaceURL, NewName(strconv.Itoa(j), id))
 		if Equal(id, i) {
 			t.Errorf("Expected UUIDs generated with the same namespace and different names to be different but got: %s and %s", id, i)
 		}
@@ -106,6 +114,66 @@ func TestUUID_NewV4Bulk(t *testing.T) {
 	}
 }
 
+func TestUUID_NewV4_entropyPool(t *testing.T) {
+	SetupEntropyPool(DefaultEntropyPoolSize)
+	defer SetupEntropyPool(0)
+
+	seen := make(map[string]bool)
+	for i := 0; i < DefaultEntropyPoolSize; i++ {
+		u := NewV4()
+		if u.Version() != 4 || u.Variant() != ReservedRFC4122 {
+			t.Fatalf("Expected a version 4 RFC4122 UUID but got %s", u)
+		}
+		if seen[u.String()] {
+			t.Fatalf("Expected unique UUIDs but %s was repeated", u)
+		}
+		seen[u.String()] = true
+	}
+
+	// handed out bytes are wiped
+	o := entropyBuffers.Get().(*entropyBuffer)
+	if o.offset > 0 && !bytes.Equal(o.data[:o.offset], make([]byte, o.offset)) {
+		t.Error("Expected the used entropy to be zeroed")
+	}
+	entropyBuffers.Put(o)
+}
+
+func BenchmarkUUID_NewV4(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NewV4()
+	}
+}
+
+func BenchmarkUUID_NewV4_entropyPool(b *testing.B) {
+	SetupEntropyPool(DefaultEntropyPoolSize)
+	defer SetupEntropyPool(0)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NewV4()
+	}
+}
+
+func BenchmarkUUID_NewV4Parallel(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			NewV4()
+		}
+	})
+}
+
+func BenchmarkUUID_NewV4Parallel_entropyPool(b *testing.B) {
+	SetupEntropyPool(DefaultEntropyPoolSize)
+	defer SetupEntropyPool(0)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			NewV4()
+		}
+	})
+}
+
 // Tests NewV5
 func TestUUID_NewV5(t *testing.T) {
 	u := NewV5(NamespaceURL, goLang)
@@ -142,7 +210,7 @@ func TestUUID_NewV5(t *testing.T) {
 		u, u2, u3, u4,
 	}
 	for j, id := range ids {
-		i := NewV5(NamespaceURL, NewName(string(j), id))
+		i := NewV5(NamespaceURL, NewName(strconv.Itoa(j), id))
 		if Equal(id, i) {
 			t.Errorf("Expected UUIDs generated with the same namespace and different names to be different but got: %s and %s", id, i)
 		}
@@ -170,7 +238,7 @@ func TestUUID_EachIsUnique(t *testing.T) {
 	}
 	ids = make([]UUID, s)
 	for i := 0; i < s; i++ {
-		u := NewV3(NamespaceDNS, NewName(string(i), Name(goLang)))
+		u := NewV3(NamespaceDNS, NewName(strconv.Itoa(i), Name(goLang)))
 		ids[i] = u
 		for j := 0; j < i; j++ {
 			if Equal(ids[j], u) {
@@ -190,7 +258,7 @@ func TestUUID_EachIsUnique(t *testing.T) {
 	}
 	ids = make([]UUID, s)
 	for i := 0; i < s; i++ {
-		u := NewV5(NamespaceDNS, NewName(string(i), Name(goLang)))
+		u := NewV5(NamespaceDNS, NewName(strconv.Itoa(i), Name(goLang)))
 		ids[i] = u
 		for j := 0; j < i; j++ {
 			if Equal(ids[j], u) {
@@ -207,3 +275,96 @@ func UUID_NamespaceDefaults() {
 	fmt.Println(NamespaceOID)
 	fmt.Println(NamespaceX500)
 }
+
+func TestUUID_NewV6(t *testing.T) {
+	u := NewV6()
+	if u.Version() != 6 {
+		t.Errorf("Expected correct version %d, but got %d", 6, u.Version())
+	}
+	if u.Variant() != ReservedRFC4122 {
+		t.Errorf("Expected RFC4122 variant %x, but got %x", ReservedRFC4122, u.Variant())
+	}
+	if !parseUUIDRegex.MatchString(u.String()) {
+		t.Errorf("Expected string representation to be valid, given: %s", u.String())
+	}
+
+	// RFC9562 Appendix A.5
+	v6 := formatV6(0x1EC9414C232AB00, 0x33C8, []byte{0x9F, 0x6B, 0xDE, 0xCE, 0xD8, 0x46})
+	if v6.String() != "1ec9414c-232a-6b00-b3c8-9f6bdeced846" {
+		t.Errorf("Expected the RFC9562 test vector but got %s", v6)
+	}
+	if tm, ok := timeOf(v6); !ok || tm.UTC().Format(time.RFC3339) != "2022-02-22T19:22:22Z" {
+		t.Errorf("Expected the time of the test vector but got %v", tm)
+	}
+
+	prev := NewV6()
+	for i := 0; i < 1000; i++ {
+		u := NewV6()
+		if bytes.Compare(prev.Bytes(), u.Bytes()) >= 0 {
+			t.Fatalf("Expected v6 UUIDs to sort by time but %s came before %s", prev, u)
+		}
+		prev = u
+	}
+}
+
+func TestUUID_NewV8Hash(t *testing.T) {
+	// RFC9562 Appendix B.2
+	u := NewV8Hash(NamespaceDNS, Name("www.example.com"), sha256.New)
+	if u.String() != "5c146b14-3c52-8afd-938a-375d0df1fbf6" {
+		t.Errorf("Expected the RFC9562 test vector but got %s", u)
+	}
+	for _, h := range []func() hash.Hash{sha256.New, sha512.New, sha512.New512_256} {
+		u := NewV8Hash(NamespaceURL, goLang, h)
+		if u.Version() != 8 || u.Variant() != ReservedRFC4122 {
+			t.Errorf("Expected a version 8 RFC4122 UUID but got %s", u)
+		}
+		if !Equal(u, NewV8Hash(NamespaceURL, goLang, h)) {
+			t.Error("Expected the same name to give the same UUID")
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for a hash shorter than 128 bits")
+		}
+	}()
+	NewV8Hash(NamespaceURL, goLang, func() hash.Hash { return crc32.NewIEEE() })
+}
+
+func TestUUID_NewV8HMAC(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	email := Name("someone@example.com")
+	u := NewV8HMAC(key, NamespaceURL, email)
+	mac := hmac.New(sha256.New, key)
+	mac.Write(NamespaceURL.Bytes())
+	mac.Write([]byte(email))
+	expected := new(Array)
+	expected.Unmarshal(mac.Sum(nil)[:length])
+	expected.setRFC4122Variant()
+	expected.setVersion(8)
+	if !Equal(u, expected) {
+		t.Errorf("Expected the first 128 bits of the HMAC-SHA256 but got %s", u)
+	}
+	if u.Version() != 8 || u.Variant() != ReservedRFC4122 {
+		t.Errorf("Expected a version 8 RFC4122 UUID but got %s", u)
+	}
+	if !Equal(u, NewV8HMAC(key, NamespaceURL, email)) {
+		t.Error("Expected the same key and name to give the same UUID")
+	}
+	if Equal(u, NewV8HMAC([]byte("another key"), NamespaceURL, email)) {
+		t.Error("Expected a different key to give a different UUID")
+	}
+	if Equal(u, NewV8Hash(NamespaceURL, email, sha256.New)) {
+		t.Error("Expected a keyed UUID to differ from an unkeyed one")
+	}
+	h := NewV8HMACHasher(key, NamespaceURL)
+	h.WriteString(string(email))
+	if !Equal(u, h.UUID()) {
+		t.Errorf("Expected NewV8HMACHasher to match NewV8HMAC but got %s", h.UUID())
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for an empty key")
+		}
+	}()
+	NewV8HMAC(nil, NamespaceURL, email)
+}
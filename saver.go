@@ -6,21 +6,65 @@ package uuid
  ***************/
 
 import (
-	"encoding/gob"
+	"context"
+	"errors"
 	"log"
 	"os"
+	"path/filepath"
+	"sync"
 	"time"
 )
 
-func init() {
-	gob.Register(stateEntity{})
+const (
+	// The default state file name within os.TempDir()
+	stateFileName = "state.unique"
+
+	// The default permissions of the state file
+	stateFileMode os.FileMode = 0644
+
+	// The smallest block of ReserveSequences; a smaller block would
+	// wrap back to a sequence in use after a few backward clock steps
+	minReserveSequences = 16
+)
+
+// SetupFileSystemStateSaver makes a FileSystemSaver the package
+// StateSaver. An invalid configuration is returned as an error and
+// leaves the package saver unchanged.
+func SetupFileSystemStateSaver(pConfig StateSaverConfig) error {
+	return SetupFileSystemStateSaverContext(context.Background(), pConfig)
 }
 
-func SetupFileSystemStateSaver(pConfig StateSaverConfig) {
+// SetupFileSystemStateSaverContext is SetupFileSystemStateSaver with a
+// context to bound or cancel loading the state file.
+func SetupFileSystemStateSaverContext(pCtx context.Context, pConfig StateSaverConfig) error {
+	if err := validReserve(pConfig.ReserveSequences); err != nil {
+		return err
+	}
+	if pConfig.Async {
+		schedule := pConfig.SaveSchedule
+		pConfig.SaveSchedule = 0
+		return SetupCustomStateSaverContext(pCtx, NewAsyncSaver(NewFileSystemSaver(pConfig), schedule))
+	}
+	return SetupCustomStateSaverContext(pCtx, NewFileSystemSaver(pConfig))
+}
+
+// NewFileSystemSaver creates a FileSystemSaver from the configuration
+// Use SetupFileSystemStateSaver to make it the package saver
+func NewFileSystemSaver(pConfig StateSaverConfig) *FileSystemSaver {
 	saver := &FileSystemSaver{}
 	saver.saveReport = pConfig.SaveReport
 	saver.saveSchedule = int64(pConfig.SaveSchedule)
-	SetupCustomStateSaver(saver)
+	saver.path = pConfig.Path
+	if saver.path == "" {
+		saver.path = filepath.Join(os.TempDir(), stateFileName)
+	}
+	saver.mode = pConfig.FileMode
+	if saver.mode == 0 {
+		saver.mode = stateFileMode
+	}
+	saver.createDirs = pConfig.CreateDirs
+	saver.reserve = pConfig.ReserveSequences
+	return saver
 }
 
 // A wrapper for default setup of the FileSystemStateSaver
@@ -31,110 +75,225 @@ type StateSaverConfig struct {
 
 	// Save every x nanoseconds
 	SaveSchedule time.Duration
-}
 
-// ***********************************************  StateEntity
+	// Save from a background goroutine through an AsyncSaver so that
+	// v1 generation does not wait for the disk
+	// Call Shutdown before exit to save the final state
+	Async bool
+
+	// The state file to use
+	// Defaults to state.unique in os.TempDir()
+	// Processes which share a path take turns through an advisory
+	// lock on Path + ".lock"
+	Path string
+
+	// The permissions of a newly created state file
+	// Defaults to 0644
+	FileMode os.FileMode
+
+	// Whether to create missing parent directories of Path
+	CreateDirs bool
 
-// StateEntity acts as a marshaller struct for the state
-type stateEntity struct {
-	Past     Timestamp
-	Node     []byte
-	Sequence uint16
+	// The number of clock sequences each process reserves from the
+	// state file at startup; 0 disables reservation.
+	// It must be a power of two from 16 to 16384 so that the blocks
+	// divide the clock sequences evenly and each block survives a few
+	// backward clock steps.
+	// The process then only uses sequences within its own block so
+	// processes sharing a node do not hand out the same v1 UUID.
+	// Blocks are handed out in turn from the next block recorded in
+	// the state file and reservations are not released, so the
+	// 16384/ReserveSequences blocks wrap around: a process gets the
+	// block of a process still running once that many processes have
+	// started after it. Size the blocks for the number of processes
+	// started over the lifetime of the longest running one.
+	ReserveSequences int
 }
 
 // This implements the StateSaver interface for UUIDs
+//
+// The state file is a versioned JSON document with a checksum; see
+// statefile.go for the layout. A file which fails the checksum is
+// reported as ErrCorruptState.
+//
+// The file is never written in place. Each save writes a temporary
+// file in the same directory, syncs it to disk and renames it over
+// the state file, so a crash leaves either the old or the new state.
+//
+// Loads and saves hold an advisory lock on a sibling lock file so
+// that several processes can share one state file.
 type FileSystemSaver struct {
-	cache        *os.File
-	saveState    uint64
+	sync.Mutex
+
+	// the next time the state will be saved
+	next Timestamp
+
 	saveReport   bool
 	saveSchedule int64
+	path         string
+	mode         os.FileMode
+	createDirs   bool
+	reserve      int
 }
 
 // Saves the current state of the generator
 // If the scheduled file save is reached then the file is synced
-func (o *FileSystemSaver) Save(pState *State) {
-	if pState.past >= pState.next {
-		err := o.open()
-		defer o.cache.Close()
-		if err != nil {
-			log.Println("uuid.State.save:", err)
-			return
-		}
-		// do the save
-		o.encode(pState)
-		// a tick is 100 nano seconds
-		pState.next = pState.past + Timestamp(o.saveSchedule / 100)
-		if o.saveReport {
-			log.Printf("UUID STATE: SAVED %d", pState.past)
+func (o *FileSystemSaver) Save(pCtx context.Context, pSnapshot Snapshot) error {
+	o.Lock()
+	defer o.Unlock()
+	if pSnapshot.past < o.next {
+		return nil
+	}
+	if err := pCtx.Err(); err != nil {
+		return err
+	}
+	err := o.locked(func() error {
+		// keep the reservations made by other processes
+		entity, _, err := o.decode()
+		if os.IsNotExist(err) {
+			entity = new(stateEntity)
+		} else if err != nil {
+			// never overwrite a state which cannot be read
+			return err
 		}
+		entity.Past, entity.Node, entity.Sequence = pSnapshot.past, pSnapshot.node, pSnapshot.sequence
+		entity.HighWater = pSnapshot.highWater
+		return o.encode(entity)
+	})
+	if err != nil {
+		return err
 	}
+	// a tick is 100 nano seconds
+	o.next = pSnapshot.past + Timestamp(o.saveSchedule/100)
+	if o.saveReport {
+		log.Printf("UUID STATE: SAVED %d", pSnapshot.past)
+	}
+	return nil
 }
 
-func (o *FileSystemSaver) Init(pState *State) {
-	pState.saver = o
-	err := o.open()
-	defer o.cache.Close()
-	if err != nil {
-		if os.IsNotExist(err) {
-			log.Printf("'%s' created\n", "uuid.SaveState")
-			var err error
-			o.cache, err = os.Create(os.TempDir() + "/state.unique")
-			if err != nil {
-				log.Println("uuid.State.init: SaveState error:", err)
-				goto pastInit
+// Loads the state file
+// A missing file is created on the first save. A corrupt file is
+// reported as ErrCorruptState and a file of an unsupported version
+// with an error; neither is ever written over, so Save fails until
+// the file is repaired or removed.
+func (o *FileSystemSaver) Init(pCtx context.Context) (Snapshot, error) {
+	o.Lock()
+	defer o.Unlock()
+	if err := pCtx.Err(); err != nil {
+		return Snapshot{}, err
+	}
+	if err := validReserve(o.reserve); err != nil {
+		return Snapshot{}, err
+	}
+	reserve := uint16(o.reserve)
+	var snapshot Snapshot
+	err := o.locked(func() error {
+		entity, version, err := o.decode()
+		if err == nil {
+			snapshot = Snapshot{past: entity.Past, node: entity.Node, sequence: entity.Sequence & sequenceMask, highWater: entity.HighWater}
+			// rewrite older versions in the current format
+			if reserve == 0 && version == stateFormatVersion {
+				return nil
 			}
-			o.encode(pState)
+		} else if !os.IsNotExist(err) {
+			return err
+		} else if reserve == 0 {
+			return nil
 		} else {
-			log.Println("uuid.State.init: SaveState error:", err)
-			goto pastInit
+			log.Printf("'%s' created\n", o.path)
+			entity = new(stateEntity)
 		}
+		if reserve > 0 {
+			// start on a block boundary so that blocks never overlap
+			start := (entity.Reserved + reserve - 1) &^ (reserve - 1) & sequenceMask
+			snapshot.sequenceStart, snapshot.sequenceSize = start, reserve
+			entity.Reserved = (start + reserve) & sequenceMask
+		}
+		return o.encode(entity)
+	})
+	o.next = snapshot.past
+	return snapshot, err
+}
+
+// Close does nothing as the state file is only open while saving
+func (o *FileSystemSaver) Close() error {
+	return nil
+}
+
+// Checks that a ReserveSequences divides the clock sequences into
+// equal blocks of at least minReserveSequences
+func validReserve(pReserve int) error {
+	if pReserve == 0 {
+		return nil
 	}
-	err = o.decode(pState)
-	if err != nil {
-		goto pastInit
-	}
-	pState.randomSequence = false
-pastInit:
-	if timestamp() <= pState.past {
-		pState.sequence++
+	if pReserve < minReserveSequences || pReserve > sequenceMask+1 || pReserve&(pReserve-1) != 0 {
+		return errors.New("uuid.FileSystemSaver: ReserveSequences must be 0 or a power of two from 16 to 16384")
 	}
-	pState.next = pState.past
+	return nil
 }
 
-func (o *FileSystemSaver) reset() {
-	o.cache.Seek(0, 0)
+// Runs the function while holding the lock file
+func (o *FileSystemSaver) locked(pFunc func() error) error {
+	if o.createDirs {
+		if err := os.MkdirAll(filepath.Dir(o.path), 0755); err != nil {
+			return err
+		}
+	}
+	f, err := lockFile(o.path+".lock", o.mode)
+	if err != nil {
+		return err
+	}
+	defer unlockFile(f)
+	return pFunc()
 }
 
-func (o *FileSystemSaver) open() error {
-	var err error
-	o.cache, err = os.OpenFile(os.TempDir()+"/state.unique", os.O_RDWR, os.ModeExclusive)
-	return err
+// Encodes State generator data into a saved file
+func (o *FileSystemSaver) encode(pEntity *stateEntity) error {
+	data, err := encodeState(pEntity)
+	if err != nil {
+		return err
+	}
+	return o.write(data)
 }
 
-// Encodes State generator data into a saved file
-func (o *FileSystemSaver) encode(pState *State) {
-	// ensure reader state is ready for use
-	o.reset()
-	enc := gob.NewEncoder(o.cache)
-	// Wrap private State data into the StateEntity
-	err := enc.Encode(&stateEntity{pState.past, pState.node, pState.sequence})
+// Decodes StateEntity data from the saved file
+// Returns the version of the file format
+func (o *FileSystemSaver) decode() (*stateEntity, int, error) {
+	data, err := os.ReadFile(o.path)
 	if err != nil {
-		log.Panic("UUID.encode error:", err)
+		return nil, 0, err
 	}
+	return decodeState(data)
 }
 
-// Decodes StateEntity data into the main State
-func (o *FileSystemSaver) decode(pState *State) error {
-	o.reset()
-	dec := gob.NewDecoder(o.cache)
-	entity := stateEntity{}
-	err := dec.Decode(&entity)
+// Atomically replaces the state file with the data
+func (o *FileSystemSaver) write(pData []byte) error {
+	dir := filepath.Dir(o.path)
+	f, err := os.CreateTemp(dir, filepath.Base(o.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(pData)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), o.mode)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), o.path)
+	}
 	if err != nil {
-		log.Println("uuid.decode error:", err)
+		os.Remove(f.Name())
 		return err
 	}
-	pState.past = entity.Past
-	pState.node = entity.Node
-	pState.sequence = entity.Sequence
+	// Persist the rename; not every platform can sync a directory
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
 	return nil
 }
@@ -6,6 +6,12 @@ package uuid
  ***************/
 
 import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -27,14 +33,14 @@ func init() {
 // Tests that the schedule is run on the timeDuration
 func TestUUID_State_saveSchedule(t *testing.T) {
 
-	if state.saver != nil {
+	if saver, ok := state.saver.(*FileSystemSaver); ok {
 		count := 0
 
 		now := time.Now()
-		state.next = timestamp() + Timestamp(config.SaveSchedule/100)
+		saver.next = timestamp() + Timestamp(config.SaveSchedule/100)
 
 		for i := 0; i < 20000; i++ {
-			if timestamp() >= state.next {
+			if timestamp() >= saver.next {
 				count++
 			}
 			NewV1()
@@ -51,7 +57,7 @@ func TestUUID_State_saveSchedule(t *testing.T) {
 // Tests that the schedule saves properly when uuid are called in go routines
 func TestUUID_State_saveScheduleGo(t *testing.T) {
 
-	if state.saver != nil {
+	if saver, ok := state.saver.(*FileSystemSaver); ok {
 
 		size := 5000
 		ids := make([]UUID, size)
@@ -63,12 +69,12 @@ func TestUUID_State_saveScheduleGo(t *testing.T) {
 		mutex := &sync.Mutex{}
 
 		now := time.Now()
-		state.next = timestamp() + Timestamp(config.SaveSchedule/100)
+		saver.next = timestamp() + Timestamp(config.SaveSchedule/100)
 
 		for i := 0; i < size; i++ {
 			go func(index int) {
 				defer wg.Done()
-				if timestamp() >= state.next {
+				if timestamp() >= saver.next {
 					atomic.AddInt32(&count, 1)
 				}
 				u := NewV1()
@@ -98,3 +104,217 @@ func TestUUID_State_saveScheduleGo(t *testing.T) {
 		}
 	}
 }
+
+// Tests that savers with their own paths keep separate state
+func TestUUID_FileSystemSaver_Path(t *testing.T) {
+	dir := t.TempDir()
+	paths := []string{
+		filepath.Join(dir, "a", "state.unique"),
+		filepath.Join(dir, "b", "c", "state.unique"),
+	}
+	for i, path := range paths {
+		saver := NewFileSystemSaver(StateSaverConfig{Path: path, FileMode: 0600, CreateDirs: true})
+		if _, err := saver.Init(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if err := saver.Save(context.Background(), NewSnapshot(timestamp(), state_bytes, uint16(i+10))); err != nil {
+			t.Fatal(err)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal("Expected the state file to be created:", err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("Expected the state file mode to be %v but got %v", os.FileMode(0600), info.Mode().Perm())
+		}
+	}
+	for i, path := range paths {
+		snapshot, err := NewFileSystemSaver(StateSaverConfig{Path: path}).Init(context.Background())
+		if err != nil || snapshot.Sequence() != uint16(i+10) {
+			t.Errorf("Expected the state saved to %s but got sequence %d %v", path, snapshot.Sequence(), err)
+		}
+	}
+}
+
+func TestUUID_FileSystemSaver_CreateDirs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "state.unique")
+	NewFileSystemSaver(StateSaverConfig{Path: path}).Init(context.Background())
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected no state file without CreateDirs but got:", err)
+	}
+}
+
+// Simulates writes torn by a crash part way through
+func TestUUID_FileSystemSaver_tornWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.unique")
+	saver := NewFileSystemSaver(StateSaverConfig{Path: path})
+	if err := saver.encode(&stateEntity{Past: timestamp(), Node: state_bytes, Sequence: 42}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A crash before the rename leaves a partial temporary file
+	// which must not affect the state file
+	if err := os.WriteFile(path+".tmp123", data[:len(data)/2], 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := saver.decode(); err != nil {
+		t.Error("Expected a partial temporary file to be ignored but got:", err)
+	}
+
+	// A file written in place and torn at any length is detected
+	for i := 0; i < len(bytes.TrimSpace(data)); i++ {
+		if err := os.WriteFile(path, data[:i], 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := saver.decode(); err != ErrCorruptState {
+			t.Errorf("Expected a file torn at %d bytes to be corrupt but got: %v", i, err)
+		}
+	}
+
+	// As is any flipped bit
+	for i := 0; i < len(data); i++ {
+		torn := append([]byte(nil), data...)
+		torn[i] ^= 0x10
+		if err := os.WriteFile(path, torn, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := saver.decode(); err != ErrCorruptState {
+			t.Errorf("Expected a file with byte %d flipped to be corrupt but got: %v", i, err)
+		}
+	}
+
+	// A corrupt file is reported and never written over
+	snapshot, err := saver.Init(context.Background())
+	if err != ErrCorruptState || !snapshot.IsZero() {
+		t.Errorf("Expected a corrupt state file to be reported but got %v %v", snapshot, err)
+	}
+	corrupt, _ := os.ReadFile(path)
+	if err := saver.Save(context.Background(), NewSnapshot(timestamp(), state_bytes, 42)); err != ErrCorruptState {
+		t.Error("Expected a save over a corrupt state file to fail but got:", err)
+	}
+	if after, _ := os.ReadFile(path); !bytes.Equal(after, corrupt) {
+		t.Error("Expected the corrupt state file to be kept")
+	}
+}
+
+// Tests that a state file which cannot be read is never written over,
+// whether or not sequences are reserved
+func TestUUID_FileSystemSaver_unreadable(t *testing.T) {
+	data, _ := encodeState(stateEntityTest)
+	newer := bytes.Replace(data, []byte(`"version": 2,`), []byte(`"version": 3, "epoch": 1,`), 1)
+	corrupt := bytes.Replace(data, []byte(`"sequence": 11520`), []byte(`"sequence": 11521`), 1)
+	for _, v := range []struct {
+		name string
+		data []byte
+	}{{"newer", newer}, {"corrupt", corrupt}} {
+		for _, reserve := range []int{0, 16} {
+			path := filepath.Join(t.TempDir(), "state.unique")
+			if err := os.WriteFile(path, v.data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			saver := NewFileSystemSaver(StateSaverConfig{Path: path, ReserveSequences: reserve})
+			_, err := saver.Init(context.Background())
+			if v.name == "newer" && (err == nil || !strings.Contains(err.Error(), "unsupported state file version 3")) {
+				t.Errorf("Expected a newer state file to be unsupported with %d reserved but got %v", reserve, err)
+			}
+			if v.name == "corrupt" && err != ErrCorruptState {
+				t.Errorf("Expected a corrupt state file with %d reserved but got %v", reserve, err)
+			}
+			if err := saver.Save(context.Background(), NewSnapshot(timestamp(), state_bytes, 42)); err == nil {
+				t.Errorf("Expected a save over a %s state file to fail with %d reserved", v.name, reserve)
+			}
+			if after, _ := os.ReadFile(path); !bytes.Equal(after, v.data) {
+				t.Errorf("Expected the %s state file to be kept with %d reserved", v.name, reserve)
+			}
+		}
+	}
+}
+
+// Tests that savers sharing a state file reserve disjoint clock
+// sequence blocks even when they start at the same time
+func TestUUID_FileSystemSaver_ReserveSequences(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.unique")
+	const workers, block = 32, 16
+
+	states := make([]*State, workers)
+	var wg sync.WaitGroup
+	for i := range states {
+		states[i] = new(State)
+		states[i].node = state_bytes
+		wg.Add(1)
+		go func(s *State) {
+			defer wg.Done()
+			snapshot, err := NewFileSystemSaver(StateSaverConfig{Path: path, ReserveSequences: block}).Init(context.Background())
+			if err != nil {
+				t.Error(err)
+			}
+			s.restore(snapshot)
+		}(states[i])
+	}
+	wg.Wait()
+
+	starts := make(map[uint16]bool)
+	for _, s := range states {
+		if s.sequenceSize != block || s.sequenceStart%block != 0 {
+			t.Fatalf("Expected a reserved block of %d but got %d at %d", block, s.sequenceSize, s.sequenceStart)
+		}
+		if starts[s.sequenceStart] {
+			t.Errorf("Expected disjoint blocks but %d was reserved twice", s.sequenceStart)
+		}
+		starts[s.sequenceStart] = true
+
+		for i := 0; i < 100; i++ {
+			if i%2 == 0 {
+				s.read(s.past, s.node)
+			} else {
+				s.read(s.past-1, net.HardwareAddr(struct_bytes))
+			}
+			if s.sequence < s.sequenceStart || s.sequence >= s.sequenceStart+block {
+				t.Fatalf("Expected sequence %d to stay within block %d", s.sequence, s.sequenceStart)
+			}
+		}
+	}
+}
+
+func TestUUID_FileSystemSaver_ReserveSequences_invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.unique")
+	state.Lock()
+	saver := state.saver
+	state.Unlock()
+	for _, v := range []int{-16, -1, 1, 2, 3, 8, 24, 1000, 16384 + 1, 32768} {
+		if _, err := NewFileSystemSaver(StateSaverConfig{Path: path, ReserveSequences: v}).Init(context.Background()); err == nil {
+			t.Error("Expected error due to an invalid block size:", v)
+		}
+		if err := SetupFileSystemStateSaver(StateSaverConfig{Path: path, ReserveSequences: v}); err == nil || !strings.Contains(err.Error(), "ReserveSequences") {
+			t.Errorf("Expected an invalid block size error for %d but got %v", v, err)
+		}
+		state.Lock()
+		unchanged := state.saver == saver
+		state.Unlock()
+		if !unchanged {
+			t.Errorf("Expected an invalid block size of %d to keep the package saver", v)
+		}
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected an invalid configuration not to create the state file")
+	}
+	for _, v := range []int{16, 32, 1024, 16384} {
+		snapshot, err := NewFileSystemSaver(StateSaverConfig{Path: path, ReserveSequences: v}).Init(context.Background())
+		if err != nil || int(snapshot.sequenceSize) != v || int(snapshot.sequenceStart)%v != 0 {
+			t.Errorf("Expected an aligned block of %d but got %d at %d %v", v, snapshot.sequenceSize, snapshot.sequenceStart, err)
+		}
+	}
+
+	// a block is aligned after a smaller one
+	data, _ := encodeState(&stateEntity{Reserved: 5})
+	os.WriteFile(path, data, 0644)
+	snapshot, err := NewFileSystemSaver(StateSaverConfig{Path: path, ReserveSequences: 16}).Init(context.Background())
+	if err != nil || snapshot.sequenceStart != 16 {
+		t.Errorf("Expected the block to start at 16 but got %d %v", snapshot.sequenceStart, err)
+	}
+}
@@ -0,0 +1,180 @@
+// Package savertest checks that a uuid.StateSaver keeps to the contract
+// the uuid package relies on for unique v1 UUIDs.
+//
+// Run it from a test with a factory over a new, empty store:
+//
+//	func TestBoltSaver(t *testing.T) {
+//		db := openTestDB(t)
+//		savertest.StateSaverTest(t, func(pSchedule time.Duration) uuid.StateSaver {
+//			return NewBoltSaver(db, pSchedule)
+//		})
+//	}
+package savertest
+
+/****************
+ * Date: 19/10/26
+ * Time: 6:20 PM
+ ***************/
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twinj/uuid"
+)
+
+// The number of 100ns ticks between 15 October 1582 and 1 January 1970
+const gregorianToUNIXOffset = 0x01B21DD213814000
+
+// A Factory creates a StateSaver which saves no more often than
+// pSchedule.
+// Every saver it returns must use the same store, so that a new saver
+// sees what earlier savers saved as it would after a restart.
+type Factory func(pSchedule time.Duration) uuid.StateSaver
+
+// StateSaverTest runs the conformance tests against the savers created
+// by pFactory. The store must be empty when the test starts.
+//
+// It checks that
+//   - Init of an empty store returns a zero Snapshot
+//   - Init returns exactly what Save saved
+//   - Save honours the schedule
+//   - Save is safe for concurrent use and never saves a mix of
+//     snapshots
+//   - the package moves the clock sequence on when the saved time is
+//     ahead of the clock
+//   - Close succeeds
+//
+// The clock regression test sets a saver up as the package StateSaver
+// and leaves an empty uuid.InMemorySaver in its place.
+func StateSaverTest(t *testing.T, pFactory Factory) {
+	ctx := context.Background()
+	node := []byte{0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	base := now() - 3*ticks(time.Hour)
+
+	restart := func(t *testing.T, pSchedule time.Duration) (uuid.StateSaver, uuid.Snapshot) {
+		t.Helper()
+		saver := pFactory(pSchedule)
+		s, err := saver.Init(ctx)
+		if err != nil {
+			t.Fatal("Expected Init to succeed but got:", err)
+		}
+		return saver, s
+	}
+
+	save := func(t *testing.T, pSaver uuid.StateSaver, pSnapshot uuid.Snapshot) {
+		t.Helper()
+		if err := pSaver.Save(ctx, pSnapshot); err != nil {
+			t.Fatal("Expected Save to succeed but got:", err)
+		}
+	}
+
+	expect := func(t *testing.T, pPast uuid.Timestamp, pNode []byte, pSequence uint16) {
+		t.Helper()
+		_, s := restart(t, 0)
+		if s.Past() != pPast || !bytes.Equal(s.Node(), pNode) || s.Sequence() != pSequence {
+			t.Errorf("Expected Init to return %d %x %d but got %d %x %d",
+				pPast, pNode, pSequence, s.Past(), s.Node(), s.Sequence())
+		}
+	}
+
+	t.Run("Init", func(t *testing.T) {
+		if _, s := restart(t, 0); !s.IsZero() {
+			t.Errorf("Expected Init of an empty store to return a zero Snapshot but got %d %x %d",
+				s.Past(), s.Node(), s.Sequence())
+		}
+	})
+
+	t.Run("Save", func(t *testing.T) {
+		saver, _ := restart(t, 0)
+		save(t, saver, uuid.NewSnapshot(base, node, 0x1234))
+		expect(t, base, node, 0x1234)
+
+		save(t, saver, uuid.NewSnapshot(base+1, node, 1).WithHighWater(base+ticks(time.Second)))
+		if _, s := restart(t, 0); s.Past() != base+1 || s.HighWater() != base+ticks(time.Second) {
+			t.Errorf("Expected Init to return %d and the high-water mark %d but got %d %d",
+				base+1, base+ticks(time.Second), s.Past(), s.HighWater())
+		}
+	})
+
+	t.Run("Schedule", func(t *testing.T) {
+		saver, _ := restart(t, time.Hour)
+		save(t, saver, uuid.NewSnapshot(base+1, node, 1))
+		save(t, saver, uuid.NewSnapshot(base+2, node, 2))
+		expect(t, base+1, node, 1)
+
+		save(t, saver, uuid.NewSnapshot(base+1+ticks(time.Hour), node, 3))
+		expect(t, base+1+ticks(time.Hour), node, 3)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		saver, _ := restart(t, 0)
+		const routines, saves = 8, 100
+		base := base + 2*ticks(time.Hour)
+
+		var wg sync.WaitGroup
+		wg.Add(routines)
+		for i := 0; i < routines; i++ {
+			go func(i int) {
+				defer wg.Done()
+				n := []byte{0x02, 0, 0, 0, 0, byte(i)}
+				for j := 0; j < saves; j++ {
+					if err := saver.Save(ctx, uuid.NewSnapshot(base+uuid.Timestamp(j), n, uint16(i*saves+j))); err != nil {
+						t.Error("Expected Save to succeed but got:", err)
+						return
+					}
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		// whichever save came last, it must be saved whole
+		_, s := restart(t, 0)
+		n := s.Node()
+		if len(n) != 6 || int(n[5]) >= routines || s.Past() < base || s.Past() >= base+saves {
+			t.Fatalf("Expected one of the saved snapshots but got %d %x %d", s.Past(), n, s.Sequence())
+		}
+		if s.Sequence() != uint16(int(n[5])*saves+int(s.Past()-base)) {
+			t.Errorf("Expected the saved snapshot not to mix saves but got %d %x %d", s.Past(), n, s.Sequence())
+		}
+	})
+
+	t.Run("ClockRegression", func(t *testing.T) {
+		saver, _ := restart(t, 0)
+		const sequence = 0x100
+		save(t, saver, uuid.NewSnapshot(now()+ticks(time.Hour), node, sequence))
+		t.Cleanup(func() {
+			uuid.SetupCustomStateSaver(new(uuid.InMemorySaver))
+		})
+		if err := uuid.SetupCustomStateSaverContext(ctx, pFactory(0)); err != nil {
+			t.Fatal("Expected the saver to be set up but got:", err)
+		}
+		u, err := uuid.TryNewV1()
+		if err != nil {
+			t.Fatal("Expected a v1 UUID but got:", err)
+		}
+		b := u.Bytes()
+		if s := uint16(b[8]&0x3F)<<8 | uint16(b[9]); s <= sequence || s > sequence+3 {
+			t.Errorf("Expected the sequence to move on from %d when the clock is behind the saved time but got %d",
+				sequence, s)
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		saver, _ := restart(t, 0)
+		if err := saver.Close(); err != nil {
+			t.Error("Expected Close to succeed but got:", err)
+		}
+	})
+}
+
+func now() uuid.Timestamp {
+	return uuid.Timestamp(time.Now().UnixNano()/100) + gregorianToUNIXOffset
+}
+
+func ticks(pDuration time.Duration) uuid.Timestamp {
+	return uuid.Timestamp(pDuration / 100)
+}
@@ -0,0 +1,29 @@
+package savertest
+
+/****************
+ * Date: 19/10/26
+ * Time: 6:48 PM
+ ***************/
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/twinj/uuid"
+)
+
+func TestInMemorySaver(t *testing.T) {
+	saver := new(uuid.InMemorySaver)
+	StateSaverTest(t, func(pSchedule time.Duration) uuid.StateSaver {
+		saver.SaveSchedule = pSchedule
+		return saver
+	})
+}
+
+func TestFileSystemSaver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.unique")
+	StateSaverTest(t, func(pSchedule time.Duration) uuid.StateSaver {
+		return uuid.NewFileSystemSaver(uuid.StateSaverConfig{Path: path, SaveSchedule: pSchedule})
+	})
+}
@@ -0,0 +1,139 @@
+package uuid
+
+/****************
+ * Date: 20/10/26
+ * Time: 3:10 PM
+ ***************/
+
+import (
+	"crypto/rand"
+	"errors"
+	seed "math/rand"
+	"net"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// The most shards of a ShardedGenerator
+// A v7 shard fixes up to 10 of the random bits
+const maxShards = 1 << 10
+
+// ******************************************************  ShardedGenerator
+
+// A ShardedGenerator creates v1 and v7 UUIDs from several shards so
+// that goroutines can generate in parallel rather than queue for the
+// package lock.
+//
+// Each v1 shard owns a disjoint block of the clock sequence and each
+// v7 shard fixes the leading random bits to its own index, so the
+// shards of a generator can never create the same UUID. v7 UUIDs
+// from one shard are in order but those of different shards only
+// sort to the millisecond.
+//
+// The v1 UUIDs use a random node, created with the generator, so they
+// cannot clash with NewV1 or another generator sharing the clock
+// sequence. They are not saved by the StateSaver.
+type ShardedGenerator struct {
+	shards []generatorShard
+
+	// The shard the next UUID starts looking from
+	next uint32
+}
+
+type generatorShard struct {
+	v1 State
+	v7 ULIDGenerator
+
+	// keeps shards on separate cache lines
+	_ [64]byte
+}
+
+// NewShardedGenerator creates a generator with pShards shards, or one
+// per GOMAXPROCS if pShards is 0.
+// Returns an error if there are more than 1024 shards.
+func NewShardedGenerator(pShards int) (*ShardedGenerator, error) {
+	if pShards == 0 {
+		pShards = runtime.GOMAXPROCS(0)
+	}
+	if pShards < 0 || pShards > maxShards {
+		return nil, errors.New("uuid.NewShardedGenerator: shards must be between 1 and 1024")
+	}
+	bits := uint(0)
+	for 1<<bits < pShards {
+		bits++
+	}
+	node := make(net.HardwareAddr, 6)
+	if _, err := rand.Read(node); err != nil {
+		return nil, err
+	}
+	node[0] |= multicastBit
+
+	state.Lock()
+	policy, observer := state.policy, state.observer
+	state.Unlock()
+
+	o := &ShardedGenerator{shards: make([]generatorShard, pShards)}
+	size := uint16((sequenceMask + 1) >> bits)
+	start := uint16(seed.Int()) & sequenceMask
+	for i := range o.shards {
+		s := &o.shards[i]
+		s.v1.node = node
+		s.v1.policy = policy
+		s.v1.observer = observer
+		s.v1.reserve(start+uint16(i)*size, size)
+		s.v7.v7 = true
+		s.v7.prefix = uint16(i)
+		s.v7.prefixBits = bits
+	}
+	return o, nil
+}
+
+// Shards returns the number of shards
+func (o *ShardedGenerator) Shards() int {
+	return len(o.shards)
+}
+
+// NewV1 will generate a new RFC4122 version 1 UUID from a free shard
+// It panics with a *ClockError if the clock moved backwards and the
+// ClockFail policy was set up when the generator was created.
+func (o *ShardedGenerator) NewV1() UUID {
+	s := o.lock(func(s *generatorShard) *sync.Mutex {
+		return &s.v1.Mutex
+	})
+	defer s.v1.Unlock()
+	now, err := s.v1.clock()
+	if err != nil {
+		panic(err)
+	}
+	s.v1.read(now, s.v1.node)
+	return formatV1Into(new(Struct), now, s.v1.sequence, uint16(1), ReservedRFC4122, s.v1.node)
+}
+
+// NewV7 will generate a new RFC9562 version 7 UUID from a free shard
+func (o *ShardedGenerator) NewV7() UUID {
+	s := o.lock(func(s *generatorShard) *sync.Mutex {
+		return &s.v7.Mutex
+	})
+	defer s.v7.Unlock()
+	if err := s.v7.next(); err != nil {
+		panic(err)
+	}
+	return s.v7.format(new(Array))
+}
+
+// Locks the first shard which is free, starting from a different
+// shard on each call, or waits for one if all are busy
+func (o *ShardedGenerator) lock(pMutex func(*generatorShard) *sync.Mutex) *generatorShard {
+	n := uint32(len(o.shards))
+	i := atomic.AddUint32(&o.next, 1)
+	for j := uint32(0); j < n; j++ {
+		s := &o.shards[(i+j)%n]
+		if pMutex(s).TryLock() {
+			return s
+		}
+	}
+	s := &o.shards[i%n]
+	pMutex(s).Lock()
+	return s
+}
@@ -0,0 +1,111 @@
+package uuid
+
+/****************
+ * Date: 20/10/26
+ * Time: 3:45 PM
+ ***************/
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestUUID_NewShardedGenerator(t *testing.T) {
+	for _, v := range []int{-1, maxShards + 1} {
+		if _, err := NewShardedGenerator(v); err == nil {
+			t.Error("Expected error due to an invalid number of shards:", v)
+		}
+	}
+	g, err := NewShardedGenerator(0)
+	if err != nil || g.Shards() != runtime.GOMAXPROCS(0) {
+		t.Fatalf("Expected a shard per GOMAXPROCS but got %v %v", g, err)
+	}
+}
+
+func TestUUID_ShardedGenerator(t *testing.T) {
+	const shards, routines, each = 3, 16, 2000
+	g, err := NewShardedGenerator(shards)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mutex sync.Mutex
+	seen := make(map[string]bool, 2*routines*each)
+	blocks := make(map[uint16]bool)
+	var wg sync.WaitGroup
+	wg.Add(routines)
+	for i := 0; i < routines; i++ {
+		go func() {
+			defer wg.Done()
+			ids := make([]UUID, 0, 2*each)
+			for j := 0; j < each; j++ {
+				ids = append(ids, g.NewV1(), g.NewV7())
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			for _, u := range ids {
+				if seen[u.String()] {
+					t.Errorf("Expected unique UUIDs but %s was repeated", u)
+				}
+				seen[u.String()] = true
+				b := u.Bytes()
+				switch u.Version() {
+				case 1:
+					// the blocks are a quarter of the clock sequence
+					sequence := (uint16(b[8]&0x3F)<<8 | uint16(b[9])) - g.shards[0].v1.sequenceStart
+					blocks[(sequence&sequenceMask)>>12] = true
+				case 7:
+					if prefix := (uint16(b[6]&0x0F)<<8 | uint16(b[7])) >> 10; prefix >= shards {
+						t.Errorf("Expected the v7 prefix to be a shard but got %d", prefix)
+					}
+				default:
+					t.Errorf("Expected version 1 or 7 but got %s", u)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	for b := range blocks {
+		if b >= shards {
+			t.Errorf("Expected the v1 sequences within the shard blocks but got block %d", b)
+		}
+	}
+}
+
+// Run with -cpu 1,2,4,8 to compare the scaling of the package
+// generators and a ShardedGenerator.
+
+func BenchmarkUUID_NewV1Parallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			NewV1()
+		}
+	})
+}
+
+func BenchmarkUUID_NewV7Parallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			NewV7()
+		}
+	})
+}
+
+func BenchmarkUUID_ShardedGenerator_NewV1Parallel(b *testing.B) {
+	g, _ := NewShardedGenerator(0)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			g.NewV1()
+		}
+	})
+}
+
+func BenchmarkUUID_ShardedGenerator_NewV7Parallel(b *testing.B) {
+	g, _ := NewShardedGenerator(0)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			g.NewV7()
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package uuid
+
+/****************
+ * Date: 18/10/26
+ * Time: 10:05 PM
+ ***************/
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"unicode/utf8"
+)
+
+const (
+	// ShortAlphabet is the default alphabet of Python's shortuuid
+	// library. Visually similar characters are left out.
+	ShortAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+)
+
+var (
+	maxUUID = new(big.Int).Lsh(big.NewInt(1), length*8)
+)
+
+// **********************************************  ShortEncoder
+
+// A ShortEncoder prints a UUID as a big integer in the base of its
+// alphabet, most significant digit first and padded with the first
+// symbol to a fixed length.
+// The output is compatible with Python's shortuuid library for the
+// same alphabet.
+type ShortEncoder struct {
+	alphabet []rune
+	index    map[rune]int
+	length   int
+}
+
+// NewShortEncoder creates a ShortEncoder for the given alphabet.
+// As with shortuuid the alphabet is deduplicated and sorted, so the
+// order of the supplied symbols does not matter.
+// An empty alphabet selects ShortAlphabet.
+func NewShortEncoder(pAlphabet string) (*ShortEncoder, error) {
+	if pAlphabet == "" {
+		pAlphabet = ShortAlphabet
+	}
+	if !utf8.ValidString(pAlphabet) {
+		return nil, errors.New("uuid.NewShortEncoder: alphabet is not valid UTF-8")
+	}
+	o := &ShortEncoder{index: make(map[rune]int)}
+	for _, r := range pAlphabet {
+		if _, ok := o.index[r]; !ok {
+			o.index[r] = 0
+			o.alphabet = append(o.alphabet, r)
+		}
+	}
+	if len(o.alphabet) < 2 {
+		return nil, errors.New("uuid.NewShortEncoder: alphabet needs at least 2 symbols")
+	}
+	sort.Sort(runes(o.alphabet))
+	for i, r := range o.alphabet {
+		o.index[r] = i
+	}
+	// Find the fewest digits which can hold 128 bits
+	base := big.NewInt(int64(len(o.alphabet)))
+	for n := big.NewInt(1); n.Cmp(maxUUID) < 0; n.Mul(n, base) {
+		o.length++
+	}
+	return o, nil
+}
+
+// Alphabet returns the sorted symbols used by the encoder.
+func (o *ShortEncoder) Alphabet() string {
+	return string(o.alphabet)
+}
+
+// Length returns the number of symbols in every encoded UUID.
+func (o *ShortEncoder) Length() int {
+	return o.length
+}
+
+// Encode prints the UUID using the encoder's alphabet.
+func (o *ShortEncoder) Encode(pUUID UUID) string {
+	n := new(big.Int).SetBytes(pUUID.Bytes())
+	base := big.NewInt(int64(len(o.alphabet)))
+	digit := new(big.Int)
+	s := make([]rune, o.length)
+	for i := o.length - 1; i >= 0; i-- {
+		n.DivMod(n, base, digit)
+		s[i] = o.alphabet[digit.Int64()]
+	}
+	return string(s)
+}
+
+// Decode creates a UUID from a string produced by Encode.
+// Returns an error if the string has the wrong length, contains a
+// symbol outside the alphabet or overflows 128 bits.
+func (o *ShortEncoder) Decode(pShort string) (UUID, error) {
+	if utf8.RuneCountInString(pShort) != o.length {
+		return nil, errors.New("uuid.ShortEncoder.Decode: invalid length")
+	}
+	n := new(big.Int)
+	base := big.NewInt(int64(len(o.alphabet)))
+	for _, r := range pShort {
+		i, ok := o.index[r]
+		if !ok {
+			return nil, errors.New("uuid.ShortEncoder.Decode: invalid symbol")
+		}
+		n.Mul(n, base)
+		n.Add(n, big.NewInt(int64(i)))
+	}
+	if n.Cmp(maxUUID) >= 0 {
+		return nil, errors.New("uuid.ShortEncoder.Decode: value overflows 128 bits")
+	}
+	o2 := new(Array)
+	b := n.Bytes()
+	copy(o2[length-len(b):], b)
+	return o2, nil
+}
+
+// ***************************************************  Helpers
+
+// Sorts runes by code point as Python sorts a string
+type runes []rune
+
+func (o runes) Len() int           { return len(o) }
+func (o runes) Less(i, j int) bool { return o[i] < o[j] }
+func (o runes) Swap(i, j int)      { o[i], o[j] = o[j], o[i] }
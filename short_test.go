@@ -0,0 +1,74 @@
+package uuid
+
+/****************
+ * Date: 18/10/26
+ * Time: 10:31 PM
+ ***************/
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUUID_NewShortEncoder(t *testing.T) {
+	o, err := NewShortEncoder("")
+	if err != nil {
+		t.Fatal("Expected the default alphabet to be valid:", err)
+	}
+	if o.Alphabet() != ShortAlphabet || o.Length() != 22 {
+		t.Errorf("Expected the shortuuid defaults but got %s of length %d", o.Alphabet(), o.Length())
+	}
+	o, err = NewShortEncoder("fedcba9876543210ff")
+	if err != nil {
+		t.Fatal("Expected a valid alphabet:", err)
+	}
+	if o.Alphabet() != "0123456789abcdef" || o.Length() != 32 {
+		t.Errorf("Expected a sorted unique alphabet but got %s of length %d", o.Alphabet(), o.Length())
+	}
+	for _, v := range []string{"a", "aaaa", "\xff\xfe"} {
+		if _, err := NewShortEncoder(v); err == nil {
+			t.Error("Expected error due to invalid alphabet:", v)
+		}
+	}
+}
+
+func TestUUID_ShortEncoder_Encode(t *testing.T) {
+	o, _ := NewShortEncoder("")
+	if s := o.Encode(NamespaceDNS); s != "MAnkyno2VCnFzuVMWtxBda" {
+		t.Errorf("Expected shortuuid compatible output but got %s", s)
+	}
+	if s := o.Encode(new(Array)); s != strings.Repeat("2", 22) {
+		t.Errorf("Expected padding with the first symbol but got %s", s)
+	}
+	h, _ := NewShortEncoder("0123456789abcdef")
+	if s := h.Encode(NamespaceDNS); s != Formatter(NamespaceDNS, Clean) {
+		t.Errorf("Expected a hex alphabet to match the Clean format but got %s", s)
+	}
+}
+
+func TestUUID_ShortEncoder_Decode(t *testing.T) {
+	for _, a := range []string{"", "01", "0123456789abcdef", "αβγδεζηθικλμνξοπρστυφχψω"} {
+		o, err := NewShortEncoder(a)
+		if err != nil {
+			t.Fatal("Expected a valid alphabet:", err)
+		}
+		for _, u := range []UUID{NewV4(), NewV1(), NamespaceURL, new(Array)} {
+			u2, err := o.Decode(o.Encode(u))
+			if err != nil || !Equal(u, u2) {
+				t.Errorf("Expected %s to survive a round trip with %s but got %v %v", u, a, u2, err)
+			}
+		}
+	}
+	o, _ := NewShortEncoder("")
+	for _, v := range []string{
+		"",
+		"MAnkyno2VCnFzuVMWtxBd",
+		"MAnkyno2VCnFzuVMWtxBdaa",
+		"MAnkyno2VCnFzuVMWtxBd0",
+		"zzzzzzzzzzzzzzzzzzzzzz",
+	} {
+		if _, err := o.Decode(v); err == nil {
+			t.Error("Expected error due to invalid short UUID:", v)
+		}
+	}
+}
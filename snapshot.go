@@ -0,0 +1,72 @@
+package uuid
+
+/****************
+ * Date: 19/10/26
+ * Time: 7:30 PM
+ ***************/
+
+// **************************************************** Snapshot
+
+// A Snapshot is a read-only copy of the v1 generator state which is
+// handed to a StateSaver to persist and returned by its Init.
+type Snapshot struct {
+	past     Timestamp
+	node     []byte
+	sequence uint16
+
+	// A timestamp which no v1 UUID has reached, or zero
+	highWater Timestamp
+
+	// A block of clock sequences reserved for this process
+	sequenceStart uint16
+	sequenceSize  uint16
+}
+
+// NewSnapshot creates a Snapshot, for a StateSaver to return the state
+// it loaded from its store.
+func NewSnapshot(pPast Timestamp, pNode []byte, pSequence uint16) Snapshot {
+	return Snapshot{
+		past:     pPast,
+		node:     append([]byte(nil), pNode...),
+		sequence: pSequence & sequenceMask,
+	}
+}
+
+// Past returns the timestamp of the last v1 UUID
+func (o Snapshot) Past() Timestamp {
+	return o.past
+}
+
+// Node returns a copy of the node id of the last v1 UUID
+func (o Snapshot) Node() []byte {
+	if o.node == nil {
+		return nil
+	}
+	return append([]byte(nil), o.node...)
+}
+
+// Sequence returns the clock sequence
+func (o Snapshot) Sequence() uint16 {
+	return o.sequence
+}
+
+// HighWater returns a timestamp which no v1 UUID has reached, or zero
+// if there is none. An AsyncSaver saves one ahead of the clock so that
+// a restart can tell whether the clock may repeat a timestamp; a
+// StateSaver must keep it along with the rest of the state.
+func (o Snapshot) HighWater() Timestamp {
+	return o.highWater
+}
+
+// WithHighWater returns a copy of the Snapshot with the high-water
+// mark, for a StateSaver to return the mark it loaded.
+func (o Snapshot) WithHighWater(pHighWater Timestamp) Snapshot {
+	o.highWater = pHighWater
+	return o
+}
+
+// IsZero reports whether the Snapshot holds no state, as returned by
+// a StateSaver with nothing saved
+func (o Snapshot) IsZero() bool {
+	return o.past == 0 && len(o.node) == 0 && o.sequence == 0 && o.highWater == 0
+}
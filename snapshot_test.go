@@ -0,0 +1,33 @@
+package uuid
+
+/****************
+ * Date: 19/10/26
+ * Time: 8:05 PM
+ ***************/
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestUUID_NewSnapshot(t *testing.T) {
+	node := append([]byte(nil), state_bytes[:6]...)
+	s := NewSnapshot(timestamp(), node, 0xFFFF)
+	if s.Sequence() != sequenceMask {
+		t.Errorf("Expected the sequence to be masked but got %x", s.Sequence())
+	}
+	node[0] = 0
+	s.Node()[1] = 0
+	if !bytes.Equal(s.Node(), state_bytes[:6]) {
+		t.Errorf("Expected the snapshot not to share its node but got %x", s.Node())
+	}
+	if s.IsZero() || !(Snapshot{}).IsZero() || !NewSnapshot(0, nil, 0).IsZero() {
+		t.Error("Expected only a snapshot without state to be zero")
+	}
+	if h := s.WithHighWater(42); h.HighWater() != 42 || h.Past() != s.Past() || s.HighWater() != 0 {
+		t.Errorf("Expected WithHighWater to return a copy with the mark but got %d", h.HighWater())
+	}
+	if NewSnapshot(0, nil, 0).WithHighWater(1).IsZero() {
+		t.Error("Expected a snapshot with a high-water mark not to be zero")
+	}
+}
@@ -7,6 +7,7 @@ package uuid
 
 import (
 	"bytes"
+	"context"
 	"log"
 	seed "math/rand"
 	"net"
@@ -16,11 +17,61 @@ import (
 
 // **************************************************** State
 
-func SetupCustomStateSaver(pSaver StateSaver) {
+// SetupCustomStateSaver makes pSaver the package StateSaver and
+// restores the state it saved.
+// An error from Init is returned but the saver is still used: the
+// random state is kept and replaces the saved state on the next save.
+// A previous saver is closed.
+func SetupCustomStateSaver(pSaver StateSaver) error {
+	return SetupCustomStateSaverContext(context.Background(), pSaver)
+}
+
+// SetupCustomStateSaverContext is SetupCustomStateSaver with a context
+// which is passed to Init, to bound or cancel loading the state.
+func SetupCustomStateSaverContext(pCtx context.Context, pSaver StateSaver) error {
 	state.Lock()
-	pSaver.Init(&state)
+	defer state.Unlock()
+	if state.saver != nil && state.saver != pSaver {
+		if err := state.saver.Close(); err != nil {
+			log.Println("uuid.SetupCustomStateSaver: close error:", err)
+		}
+	}
+	state.saver = pSaver
+	state.err = nil
+	snapshot, err := pSaver.Init(pCtx)
+	state.restore(snapshot)
 	state.init()
-	state.Unlock()
+	return err
+}
+
+// SaveState saves the current state through the package StateSaver,
+// subject to its schedule; an AsyncSaver is flushed. The context
+// bounds or cancels the save. v1 and v6 generation saves without a
+// deadline, so use an AsyncSaver to keep a slow store off that path.
+func SaveState(pCtx context.Context) error {
+	state.Lock()
+	defer state.Unlock()
+	if state.saver == nil {
+		return nil
+	}
+	if saver, ok := state.saver.(*AsyncSaver); ok {
+		state.err = saver.Save(pCtx, state.snapshot())
+		if state.err == nil {
+			state.err = saver.Flush(pCtx)
+		}
+		return state.err
+	}
+	state.persistContext(pCtx)
+	return state.err
+}
+
+// SaverErr returns the error of the last failed save, or nil if the
+// last save succeeded. While it is not nil, v1 UUIDs may be repeated
+// after a restart.
+func SaverErr() error {
+	state.Lock()
+	defer state.Unlock()
+	return state.err
 }
 
 // Holds package information about the current
@@ -38,40 +89,148 @@ type State struct {
 	// the last time UUID was saved
 	past Timestamp
 
-	// the next time the state will be saved
-	next Timestamp
-
 	// the last node which saved a UUID
 	node []byte
 
+	// A copy of node for snapshots, made when node changes
+	snapshotNode []byte
+
+	// The node chosen by a SetupNode function, if any
+	setupNode net.HardwareAddr
+
 	// An iterated value to help ensure different
 	// values across the same domain
 	sequence uint16
 
+	// The block of clock sequences reserved for this process
+	// A zero size means any sequence may be used
+	sequenceStart uint16
+	sequenceSize  uint16
+
+	// The highest timestamp before the clock last moved backwards
+	// The bumped sequence is kept until the clock passes it
+	regressed Timestamp
+
 	sync.Mutex
 
 	// save state interface
 	saver StateSaver
+
+	// The error of the last save
+	err error
+
+	// The last clock reading and the timestamp of the last UUID,
+	// which may be ahead of the reading
+	clockReading  Timestamp
+	lastTimestamp Timestamp
+
+	// The number of ticks UUIDs may run ahead of the clock
+	idsPerTimestamp Timestamp
+
+	// What to do when the clock moves backwards
+	policy ClockPolicy
+
+	// Told about clock events
+	observer ClockObserver
 }
 
 // Changes the state with current data
-// Compares the current found node to the last node stored,
-// If they are the same or randomSequence is already set due
+// If the clock is not past the last UUID, which clock only allows
+// under ClockBump, the sequence is incremented whatever the node.
+// The bumped sequence is kept while the clock is within the time it
+// went back over, where a new random sequence could repeat an
+// earlier UUID.
+// Otherwise it compares the current found node to the last node
+// stored. If they are the same or randomSequence is already set due
 // to an earlier read issue then the sequence is randomly generated
-// else if there is an issue with the time the sequence is incremented
 func (o *State) read(pNow Timestamp, pNode net.HardwareAddr) {
-	if bytes.Equal([]byte(pNode), o.node) || o.randomSequence {
-		o.sequence = uint16(seed.Int()) & 0x3FFF
-	} else if pNow < o.past {
-		o.sequence++
+	if pNow <= o.past {
+		if o.past > o.regressed {
+			o.regressed = o.past
+		}
+		o.nextSequence()
+	} else if pNow <= o.regressed {
+		// keep the bumped sequence
+	} else if bytes.Equal([]byte(pNode), o.node) || o.randomSequence {
+		o.newSequence()
 	}
 	o.past = pNow
 	o.node = pNode
 }
 
+// Restricts the clock sequence to a reserved block
+func (o *State) reserve(pStart, pSize uint16) {
+	o.sequenceStart = pStart & sequenceMask
+	o.sequenceSize = pSize
+	o.newSequence()
+}
+
+// Randomly generates the clock sequence within the reserved block
+func (o *State) newSequence() {
+	if o.sequenceSize == 0 {
+		o.sequence = uint16(seed.Int()) & sequenceMask
+		return
+	}
+	o.sequence = (o.sequenceStart + uint16(seed.Intn(int(o.sequenceSize)))) & sequenceMask
+}
+
+// Increments the clock sequence wrapping within the reserved block
+func (o *State) nextSequence() {
+	if o.observer != nil {
+		o.observer.SequenceBump()
+	}
+	if o.sequenceSize == 0 {
+		o.sequence = (o.sequence + 1) & sequenceMask
+		return
+	}
+	offset := (o.sequence - o.sequenceStart) & sequenceMask
+	o.sequence = (o.sequenceStart + (offset+1)%o.sequenceSize) & sequenceMask
+}
+
+// A copy of the state for a StateSaver
+func (o *State) snapshot() Snapshot {
+	if !bytes.Equal(o.snapshotNode, o.node) {
+		o.snapshotNode = append([]byte(nil), o.node...)
+	}
+	return Snapshot{
+		past:     o.past,
+		node:     o.snapshotNode,
+		sequence: o.sequence,
+	}
+}
+
+// Loads the state a StateSaver saved earlier
+// If the clock is not past the saved time or high-water mark the
+// clock sequence is incremented so that UUIDs are not repeated after
+// a restart, and kept until the clock passes them. The mark is not
+// the time of a UUID so it does not become the last timestamp.
+func (o *State) restore(pSnapshot Snapshot) {
+	if !pSnapshot.IsZero() {
+		o.past, o.node, o.sequence = pSnapshot.past, pSnapshot.node, pSnapshot.sequence
+		o.randomSequence = false
+		o.regressed = o.past
+		if pSnapshot.highWater > o.regressed {
+			o.regressed = pSnapshot.highWater
+		}
+		if now := timestamp(); now <= o.regressed {
+			o.nextSequence()
+		}
+	}
+	if pSnapshot.sequenceSize > 0 {
+		o.reserve(pSnapshot.sequenceStart, pSnapshot.sequenceSize)
+	}
+}
+
 func (o *State) persist() {
+	o.persistContext(context.Background())
+}
+
+func (o *State) persistContext(pCtx context.Context) {
 	if o.saver != nil {
-		o.saver.Save(o)
+		o.err = o.saver.Save(pCtx, o.snapshot())
+		if o.err != nil {
+			log.Println("uuid.State.persist: save error:", o.err)
+		}
 	}
 }
 
@@ -83,20 +242,23 @@ func (o *State) persist() {
 // thirdly it will check the state of the clock
 func (o *State) init() {
 	if o.saver != nil {
-		intfcs, err := net.Interfaces()
-		if err != nil {
-			log.Println("uuid.State.init: address error: will generate random node id instead", err)
-			return
-		}
-		a := getHardwareAddress(intfcs)
+		a := o.setupNode
 		if a == nil {
-			log.Println("uuid.State.init: address error: will generate random node id instead", err)
-			return
+			intfcs, err := net.Interfaces()
+			if err != nil {
+				log.Println("uuid.State.init: address error: will generate random node id instead", err)
+				return
+			}
+			a = getHardwareAddress(intfcs)
+			if a == nil {
+				log.Println("uuid.State.init: address error: will generate random node id instead", err)
+				return
+			}
 		}
 		// Don't use random as we have a real address
 		o.randomSequence = false
 		if bytes.Equal([]byte(a), state.node) {
-			state.sequence++
+			state.nextSequence()
 		}
 		state.node = a
 		state.randomNode = false
@@ -125,13 +287,21 @@ func getHardwareAddress(pInterfaces []net.Interface) net.HardwareAddr {
 
 // Use this interface to setup a custom state saver if you wish to have
 // v1 UUIDs based on your node id and constant time.
+//
+// The savertest package checks that an implementation keeps to the
+// contract described here.
 type StateSaver interface {
-	// Init is run if Setup() is false
-	// Init should setup the system to save the state
-	Init(*State)
+	// Init is called once by SetupCustomStateSaver
+	// Init should setup the system to save the state and return the
+	// state saved last, or a zero Snapshot if nothing was saved
+	Init(context.Context) (Snapshot, error)
 
-	// Save saves the state and is called only if const V1Save and
-	// Setup() is true
-	Save(*State)
-}
+	// Save is called after each v1 UUID
+	// Save should persist the Snapshot, no more often than the saver's
+	// schedule, and must be safe for concurrent use
+	Save(context.Context, Snapshot) error
 
+	// Close releases the store; it is called when the saver is
+	// replaced
+	Close() error
+}
@@ -7,8 +7,11 @@ package uuid
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"net"
+	"path/filepath"
 	"testing"
 )
 
@@ -87,6 +90,142 @@ func TestUUID_State_read(t *testing.T) {
 	}
 }
 
+func TestUUID_State_read_regression(t *testing.T) {
+	node := net.HardwareAddr(state_bytes)
+	s := new(State)
+	s.node = state_bytes
+	past := timestamp()
+	s.read(past, node)
+	sequence := s.sequence
+
+	// the clock goes back and then steps forward within the window
+	s.read(past-10, node)
+	bumped := (sequence + 1) & sequenceMask
+	for now := past - 9; now <= past; now++ {
+		s.read(now, node)
+		if s.sequence != bumped {
+			t.Fatalf("Expected the bumped sequence %d to be kept at %d ticks back but got %d", bumped, past-now, s.sequence)
+		}
+	}
+
+	// past the window the sequence is random again
+	for i := Timestamp(1); s.sequence == bumped && i < 100; i++ {
+		s.read(past+i, node)
+	}
+	if s.sequence == bumped {
+		t.Error("Expected a random sequence once the clock passed the window")
+	}
+}
+
 func TestUUID_State_init(t *testing.T) {
 
 }
+
+func TestUUID_State_restore(t *testing.T) {
+	s := new(State)
+	s.randomSequence = true
+	s.restore(Snapshot{})
+	if !s.randomSequence {
+		t.Error("A zero snapshot should keep the random state")
+	}
+
+	past := timestamp() - 100
+	s.restore(NewSnapshot(past, state_bytes, 42))
+	if s.past != past || !bytes.Equal(s.node, state_bytes) || s.sequence != 42 || s.randomSequence {
+		t.Error("The state should be restored from the snapshot", s.past, s.node, s.sequence)
+	}
+
+	// the clock is behind the saved time
+	s.restore(NewSnapshot(timestamp()+ticksPerSecond, state_bytes, 42))
+	if s.sequence != 43 {
+		t.Error("The sequence should increment when the clock is behind the saved time", s.sequence)
+	}
+}
+
+type failingSaver struct {
+	closed bool
+}
+
+func (o *failingSaver) Init(pCtx context.Context) (Snapshot, error) {
+	return Snapshot{}, errors.New("init")
+}
+
+func (o *failingSaver) Save(pCtx context.Context, pSnapshot Snapshot) error {
+	return errors.New("save")
+}
+
+func (o *failingSaver) Close() error {
+	o.closed = true
+	return nil
+}
+
+func TestUUID_SetupCustomStateSaver(t *testing.T) {
+	defer SetupFileSystemStateSaver(config)
+
+	saver := new(failingSaver)
+	if err := SetupCustomStateSaver(saver); err == nil {
+		t.Error("Expected the Init error to be returned")
+	}
+	if SaverErr() != nil {
+		t.Error("Expected no save error before a save")
+	}
+	NewV1()
+	if SaverErr() == nil {
+		t.Error("Expected the save error to be reported")
+	}
+	if err := SetupCustomStateSaver(new(InMemorySaver)); err != nil {
+		t.Error("Expected no error but got:", err)
+	}
+	if !saver.closed {
+		t.Error("Expected the replaced saver to be closed")
+	}
+	NewV1()
+	if SaverErr() != nil {
+		t.Error("Expected the save error to be cleared")
+	}
+}
+
+func TestUUID_SetupCustomStateSaverContext(t *testing.T) {
+	defer SetupFileSystemStateSaver(config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	path := filepath.Join(t.TempDir(), "state.unique")
+	if err := SetupFileSystemStateSaverContext(ctx, StateSaverConfig{Path: path}); err != context.Canceled {
+		t.Error("Expected the context to reach Init but got:", err)
+	}
+	if err := SetupFileSystemStateSaverContext(context.Background(), StateSaverConfig{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	NewV1()
+	if err := SaveState(ctx); err != context.Canceled || SaverErr() != context.Canceled {
+		t.Error("Expected the context to reach Save but got:", err)
+	}
+	if err := SaveState(context.Background()); err != nil || SaverErr() != nil {
+		t.Error("Expected the state to be saved but got:", err)
+	}
+	if err := SetupFileSystemStateSaverContext(context.Background(), StateSaverConfig{Path: path, Async: true}); err != nil {
+		t.Fatal(err)
+	}
+	NewV1()
+	if err := SaveState(context.Background()); err != nil {
+		t.Error("Expected an AsyncSaver to be flushed but got:", err)
+	}
+	Shutdown(context.Background())
+	if err := SaveState(ctx); err != nil {
+		t.Error("Expected nothing to save without a saver but got:", err)
+	}
+}
+
+func TestUUID_State_snapshot(t *testing.T) {
+	s := new(State)
+	s.node = append([]byte(nil), state_bytes[:6]...)
+	first := s.snapshot()
+	if n := testing.AllocsPerRun(100, func() { s.snapshot() }); n != 0 {
+		t.Errorf("Expected the node to be copied only when it changes but got %v allocations", n)
+	}
+	s.node = net.HardwareAddr{1, 2, 3, 4, 5, 6}
+	if second := s.snapshot(); !bytes.Equal(second.Node(), s.node) || !bytes.Equal(first.Node(), state_bytes[:6]) {
+		t.Errorf("Expected a changed node to be copied but got %x and %x", first.Node(), second.Node())
+	}
+}
@@ -0,0 +1,210 @@
+package uuid
+
+/****************
+ * Date: 19/10/26
+ * Time: 3:15 PM
+ ***************/
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// The state file is a JSON document so that it can be read during an
+// incident and migrated between releases:
+//
+//	{
+//	  "format": "github.com/twinj/uuid/state",
+//	  "version": 2,
+//	  "past": 139797048431234567,
+//	  "time": "2026-10-19T15:15:00.1234567Z",
+//	  "node": "00:c0:4f:d4:30:c8",
+//	  "sequence": 11520,
+//	  "reserved": 32,
+//	  "high_water": 139797048631234567,
+//	  "checksum": "crc32c:8f1a2b3c"
+//	}
+//
+// past is the last v1 timestamp in 100ns ticks since 15 October 1582
+// and time is the same instant for people; only past is read back.
+// node is the last node id. sequence is the clock sequence and
+// reserved the start of the next block for ReserveSequences.
+// high_water is the high-water mark of an AsyncSaver, if any; it is
+// left out when zero.
+// checksum is the CRC-32 (Castagnoli) of the compact JSON encoding
+// of the document with an empty checksum.
+//
+// Earlier releases wrote a gob encoded stateEntity, either bare
+// (version 0) or after a checksum header (version 1). Both are still
+// read and are replaced with the current version on startup.
+const (
+	stateFormat        = "github.com/twinj/uuid/state"
+	stateFormatVersion = 2
+
+	// Identifies a version 1 state file with a checksum header
+	stateMagic = "UUST"
+
+	// The magic, checksum and payload length
+	stateHeaderLength = 12
+
+	stateChecksumPrefix = "crc32c:"
+)
+
+var (
+	// ErrCorruptState is reported when the state file fails its
+	// checksum, for example after a torn write.
+	ErrCorruptState = errors.New("uuid.FileSystemSaver: corrupt state file")
+
+	stateCRCTable = crc32.MakeTable(crc32.Castagnoli)
+)
+
+func init() {
+	gob.Register(stateEntity{})
+}
+
+// ***********************************************  StateEntity
+
+// StateEntity acts as a marshaller struct for the state
+type stateEntity struct {
+	Past     Timestamp
+	Node     []byte
+	Sequence uint16
+
+	// The start of the next clock sequence block to reserve
+	Reserved uint16
+
+	// The high-water mark of the Snapshot
+	HighWater Timestamp
+}
+
+// The JSON document of the state file
+type stateDocument struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	Past      Timestamp `json:"past"`
+	Time      string    `json:"time"`
+	Node      string    `json:"node"`
+	Sequence  uint16    `json:"sequence"`
+	Reserved  uint16    `json:"reserved"`
+	HighWater Timestamp `json:"high_water,omitempty"`
+	Checksum  string    `json:"checksum"`
+}
+
+// Encodes the entity in the current version
+func encodeState(pEntity *stateEntity) ([]byte, error) {
+	doc := stateDocument{
+		Format:    stateFormat,
+		Version:   stateFormatVersion,
+		Past:      pEntity.Past,
+		Time:      pEntity.Past.Unix().UTC().Format(time.RFC3339Nano),
+		Node:      net.HardwareAddr(pEntity.Node).String(),
+		Sequence:  pEntity.Sequence,
+		Reserved:  pEntity.Reserved,
+		HighWater: pEntity.HighWater,
+	}
+	sum, err := doc.checksum()
+	if err != nil {
+		return nil, err
+	}
+	doc.Checksum = sum
+	data, err := json.MarshalIndent(&doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Decodes any version of the state file
+// Returns the version which was read
+func decodeState(pData []byte) (*stateEntity, int, error) {
+	if bytes.HasPrefix(bytes.TrimLeft(pData, " \t\r\n"), []byte("{")) {
+		entity, err := decodeStateDocument(pData)
+		return entity, stateFormatVersion, err
+	}
+	if bytes.HasPrefix(pData, []byte(stateMagic)) {
+		if len(pData) < stateHeaderLength {
+			return nil, 1, ErrCorruptState
+		}
+		payload := pData[stateHeaderLength:]
+		if binary.BigEndian.Uint32(pData[8:12]) != uint32(len(payload)) ||
+			binary.BigEndian.Uint32(pData[4:8]) != crc32.Checksum(payload, stateCRCTable) {
+			return nil, 1, ErrCorruptState
+		}
+		entity, err := decodeStateGob(payload)
+		return entity, 1, err
+	}
+	entity, err := decodeStateGob(pData)
+	return entity, 0, err
+}
+
+func decodeStateDocument(pData []byte) (*stateEntity, error) {
+	// Check the format and version first, as a later version may
+	// have fields this one does not know
+	header := struct {
+		Format  string `json:"format"`
+		Version *int   `json:"version"`
+	}{}
+	if err := json.Unmarshal(pData, &header); err != nil || header.Format != stateFormat || header.Version == nil {
+		return nil, ErrCorruptState
+	}
+	if *header.Version != stateFormatVersion {
+		return nil, fmt.Errorf("uuid.FileSystemSaver: unsupported state file version %d", *header.Version)
+	}
+	doc := stateDocument{}
+	// A damaged key would otherwise leave its field at zero
+	dec := json.NewDecoder(bytes.NewReader(pData))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, ErrCorruptState
+	}
+	// Nothing may follow the document
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, ErrCorruptState
+	}
+	sum := doc.Checksum
+	doc.Checksum = ""
+	if expected, err := doc.checksum(); err != nil || sum != expected {
+		return nil, ErrCorruptState
+	}
+	node, err := parseNode(doc.Node)
+	if err != nil {
+		return nil, ErrCorruptState
+	}
+	return &stateEntity{doc.Past, node, doc.Sequence, doc.Reserved, doc.HighWater}, nil
+}
+
+func decodeStateGob(pData []byte) (*stateEntity, error) {
+	entity := new(stateEntity)
+	if err := gob.NewDecoder(bytes.NewReader(pData)).Decode(entity); err != nil {
+		return nil, ErrCorruptState
+	}
+	return entity, nil
+}
+
+// The checksum of the document with an empty checksum
+func (o stateDocument) checksum() (string, error) {
+	o.Checksum = ""
+	data, err := json.Marshal(&o)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%08x", stateChecksumPrefix, crc32.Checksum(data, stateCRCTable)), nil
+}
+
+// Parses colon separated hex bytes of any length
+func parseNode(pNode string) ([]byte, error) {
+	if pNode == "" {
+		return nil, nil
+	}
+	return hex.DecodeString(strings.Replace(pNode, ":", "", -1))
+}
@@ -0,0 +1,126 @@
+package uuid
+
+/****************
+ * Date: 19/10/26
+ * Time: 4:02 PM
+ ***************/
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var stateEntityTest = &stateEntity{
+	Past:     Timestamp((1391463463 * 10000000) + gregorianToUNIXOffset),
+	Node:     []byte{0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8},
+	Sequence: 0x2d00,
+	Reserved: 32,
+}
+
+func TestUUID_encodeState(t *testing.T) {
+	data, err := encodeState(stateEntityTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{
+		`"format": "github.com/twinj/uuid/state"`,
+		`"version": 2`,
+		`"time": "2014-02-03T21:37:43Z"`,
+		`"node": "00:c0:4f:d4:30:c8"`,
+		`"sequence": 11520`,
+		`"checksum": "crc32c:`,
+	} {
+		if !strings.Contains(string(data), v) {
+			t.Errorf("Expected the state file to be readable and contain %s but got %s", v, data)
+		}
+	}
+	entity, version, err := decodeState(data)
+	if err != nil || version != stateFormatVersion {
+		t.Fatalf("Expected the current version to decode but got %d %v", version, err)
+	}
+	if !reflect.DeepEqual(entity, stateEntityTest) {
+		t.Errorf("Expected %v but got %v", stateEntityTest, entity)
+	}
+}
+
+func TestUUID_decodeState_versions(t *testing.T) {
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(stateEntityTest); err != nil {
+		t.Fatal(err)
+	}
+	v1 := make([]byte, stateHeaderLength)
+	copy(v1, stateMagic)
+	binary.BigEndian.PutUint32(v1[4:8], crc32.Checksum(payload.Bytes(), stateCRCTable))
+	binary.BigEndian.PutUint32(v1[8:12], uint32(payload.Len()))
+	v1 = append(v1, payload.Bytes()...)
+
+	for version, data := range [][]byte{payload.Bytes(), v1} {
+		entity, v, err := decodeState(data)
+		if err != nil || v != version {
+			t.Errorf("Expected version %d to decode but got %d %v", version, v, err)
+			continue
+		}
+		if !reflect.DeepEqual(entity, stateEntityTest) {
+			t.Errorf("Expected %v from version %d but got %v", stateEntityTest, version, entity)
+		}
+
+		// Init migrates the file to the current version
+		path := filepath.Join(t.TempDir(), "state.unique")
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		s, err := NewFileSystemSaver(StateSaverConfig{Path: path}).Init(context.Background())
+		if err != nil || s.Past() != stateEntityTest.Past || !bytes.Equal(s.Node(), stateEntityTest.Node) {
+			t.Errorf("Expected the state to be loaded from version %d", version)
+		}
+		migrated, _ := os.ReadFile(path)
+		if _, v, err := decodeState(migrated); err != nil || v != stateFormatVersion {
+			t.Errorf("Expected version %d to be migrated but got %d %v", version, v, err)
+		}
+	}
+}
+
+func TestUUID_decodeState_invalid(t *testing.T) {
+	data, _ := encodeState(stateEntityTest)
+	for _, v := range []struct {
+		old, new string
+	}{
+		{`"version": 2`, `"version": 3`},
+		{`"github.com/twinj/uuid/state"`, `"other"`},
+		{`"sequence": 11520`, `"sequence": 11521`},
+		{`"node": "00:c0:4f:d4:30:c8"`, `"node": "00:c0:4f:d4:30:cg"`},
+		{`"reserved": 32`, `"reserved": 32, "extra": 1`},
+		{"}\n", "}\n{}"},
+	} {
+		_, _, err := decodeState(bytes.Replace(data, []byte(v.old), []byte(v.new), 1))
+		if err == nil {
+			t.Errorf("Expected error when %s is replaced with %s", v.old, v.new)
+		}
+	}
+}
+
+func TestUUID_decodeState_version(t *testing.T) {
+	data, _ := encodeState(stateEntityTest)
+	for _, version := range []int{0, 1, stateFormatVersion + 1} {
+		doc := stateDocument{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatal(err)
+		}
+		doc.Version = version
+		doc.Checksum, _ = doc.checksum()
+		b, _ := json.Marshal(&doc)
+		_, _, err := decodeState(b)
+		if err == nil || err == ErrCorruptState || !strings.Contains(err.Error(), "version") {
+			t.Errorf("Expected a document of version %d to be unsupported but got %v", version, err)
+		}
+	}
+}
@@ -5,7 +5,10 @@ package uuid
  * Time: 3:34 PM
  ***************/
 
-import "net"
+import (
+	"fmt"
+	"net"
+)
 
 // Struct is used for RFC4122 Version 1 UUIDs
 type Struct struct {
@@ -74,11 +77,41 @@ func (o *Struct) UnmarshalBinary(pData []byte) error {
 }
 
 func (o Struct) String() string {
-	return formatter(&o, format)
+	var b [maxFormatLength]byte
+	a := o.array()
+	return string(currentFormat().append(b[:0], a[:]))
 }
 
-func (o Struct) Format(pFormat string) string {
-	return formatter(&o, pFormat)
+// AppendString appends the UUID printed in the default format to dst
+// and returns the extended buffer. It does not allocate when dst has
+// enough capacity and the format only uses %x and %X verbs.
+func (o Struct) AppendString(dst []byte) []byte {
+	a := o.array()
+	return currentFormat().append(dst, a[:])
+}
+
+// Lays out the fields as bytes without allocating
+func (o Struct) array() (a Array) {
+	a[0], a[1], a[2], a[3] = byte(o.timeLow>>24), byte(o.timeLow>>16), byte(o.timeLow>>8), byte(o.timeLow)
+	a[4], a[5] = byte(o.timeMid>>8), byte(o.timeMid)
+	a[6], a[7] = byte(o.timeHiAndVersion>>8), byte(o.timeHiAndVersion)
+	a[8] = o.sequenceHiAndVariant
+	a[9] = o.sequenceLow
+	copy(a[10:], o.node)
+	return
+}
+
+// Format implements fmt.Formatter. See FormatVerb.
+func (o Struct) Format(pFmt fmt.State, pVerb rune) {
+	FormatVerb(&o, pFmt, pVerb)
+}
+
+// FormatString prints the UUID in the format pattern. It replaces the
+// Format(string) string method, whose name fmt.Formatter now uses.
+//
+// Deprecated: Use Formatter.
+func (o Struct) FormatString(pFormat string) string {
+	return formatter(&o, compiledFormat(pFormat))
 }
 
 // Set the three most significant bits (bits 0, 1 and 2) of the
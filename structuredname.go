@@ -0,0 +1,106 @@
+package uuid
+
+/****************
+ * Date: 20/10/26
+ * Time: 9:20 PM
+ ***************/
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// The type of each component of a StructuredName
+const (
+	componentString byte = iota + 1
+	componentInt
+	componentUint
+	componentBytes
+	componentTime
+	componentUUID
+)
+
+// **************************************************  StructuredName
+
+// A StructuredName is a UniqueName built from typed components, such
+// as the fields of a composite key:
+//
+//	name := NewStructuredName().AddString("orders").AddInt(42).AddTime(day)
+//	id := NewV5(NamespaceURL, name)
+//
+// Each component is encoded with its type and length, so different
+// components can never encode to the same name: ("ab", "c") and
+// ("a", "bc") differ, as do the string "1" and the int 1.
+//
+// The zero value is an empty name. A StructuredName is not safe for
+// concurrent use.
+type StructuredName struct {
+	data []byte
+}
+
+// NewStructuredName creates an empty StructuredName.
+func NewStructuredName() *StructuredName {
+	return new(StructuredName)
+}
+
+// AddString adds a string component.
+func (o *StructuredName) AddString(pValue string) *StructuredName {
+	o.header(componentString, len(pValue))
+	o.data = append(o.data, pValue...)
+	return o
+}
+
+// AddInt adds a signed integer component.
+func (o *StructuredName) AddInt(pValue int64) *StructuredName {
+	o.header(componentInt, 8)
+	o.data = binary.BigEndian.AppendUint64(o.data, uint64(pValue))
+	return o
+}
+
+// AddUint adds an unsigned integer component.
+func (o *StructuredName) AddUint(pValue uint64) *StructuredName {
+	o.header(componentUint, 8)
+	o.data = binary.BigEndian.AppendUint64(o.data, pValue)
+	return o
+}
+
+// AddBytes adds a byte slice component.
+func (o *StructuredName) AddBytes(pValue []byte) *StructuredName {
+	o.header(componentBytes, len(pValue))
+	o.data = append(o.data, pValue...)
+	return o
+}
+
+// AddTime adds a time component. Only the instant is encoded, so the
+// same instant in any location gives the same name.
+func (o *StructuredName) AddTime(pValue time.Time) *StructuredName {
+	o.header(componentTime, 12)
+	o.data = binary.BigEndian.AppendUint64(o.data, uint64(pValue.Unix()))
+	o.data = binary.BigEndian.AppendUint32(o.data, uint32(pValue.Nanosecond()))
+	return o
+}
+
+// AddUUID adds a UUID component.
+func (o *StructuredName) AddUUID(pValue UUID) *StructuredName {
+	b := pValue.Bytes()
+	o.header(componentUUID, len(b))
+	o.data = append(o.data, b...)
+	return o
+}
+
+// Bytes returns the encoded name. The bytes must not be modified.
+// Use with NewV5Bytes or a NameHasher to avoid copying to a string.
+func (o *StructuredName) Bytes() []byte {
+	return o.data
+}
+
+// Returns the encoded name. Satisfies the Stringer interface.
+func (o *StructuredName) String() string {
+	return string(o.data)
+}
+
+// Writes the type and length of a component
+func (o *StructuredName) header(pType byte, pLength int) {
+	o.data = append(o.data, pType)
+	o.data = binary.AppendUvarint(o.data, uint64(pLength))
+}
@@ -0,0 +1,58 @@
+package uuid
+
+/****************
+ * Date: 20/10/26
+ * Time: 9:50 PM
+ ***************/
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestUUID_StructuredName(t *testing.T) {
+	day := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	names := []*StructuredName{
+		NewStructuredName(),
+		NewStructuredName().AddString(""),
+		NewStructuredName().AddString("ab").AddString("c"),
+		NewStructuredName().AddString("a").AddString("bc"),
+		NewStructuredName().AddString("abc"),
+		NewStructuredName().AddBytes([]byte("abc")),
+		NewStructuredName().AddString("1"),
+		NewStructuredName().AddInt(1),
+		NewStructuredName().AddUint(1),
+		NewStructuredName().AddInt(-1),
+		NewStructuredName().AddTime(day),
+		NewStructuredName().AddTime(day.Add(time.Nanosecond)),
+		NewStructuredName().AddUUID(NamespaceDNS),
+		NewStructuredName().AddBytes(NamespaceDNS.Bytes()),
+		NewStructuredName().AddString("orders").AddInt(42),
+		NewStructuredName().AddInt(42).AddString("orders"),
+	}
+	for i, n := range names {
+		for j, n2 := range names {
+			if i != j && n.String() == n2.String() {
+				t.Errorf("Expected names %d and %d to differ but both are %x", i, j, n.Bytes())
+			}
+		}
+	}
+	if Equal(NewV5(NamespaceURL, names[2]), NewV5(NamespaceURL, names[3])) {
+		t.Error("Expected different components to give different UUIDs")
+	}
+	if !Equal(NewV5(NamespaceURL, names[14]), NewV5Bytes(NamespaceURL, names[14].Bytes())) {
+		t.Error("Expected Bytes to give the same UUID as String")
+	}
+	var zero StructuredName
+	if zero.AddString("ab").String() != NewStructuredName().AddString("ab").String() {
+		t.Error("Expected the zero value to be an empty name")
+	}
+	if !bytes.Equal(NewStructuredName().AddString("ab").AddInt(1).Bytes(), []byte{1, 2, 'a', 'b', 2, 8, 0, 0, 0, 0, 0, 0, 0, 1}) {
+		t.Error("Expected each component to be its type, length and value")
+	}
+	local := day.In(time.FixedZone("UTC+10", 10*60*60))
+	if NewStructuredName().AddTime(local).String() != names[10].String() {
+		t.Error("Expected the same instant in any location to give the same name")
+	}
+}
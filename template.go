@@ -0,0 +1,208 @@
+package uuid
+
+/****************
+ * Date: 19/10/26
+ * Time: 12:10 AM
+ ***************/
+
+import (
+	"errors"
+	"strings"
+)
+
+// The nibble ranges of the RFC4122 fields which can be named in a
+// Template. Nibble 0 is the high nibble of the first byte.
+var templateFields = map[string][2]int{
+	"hex":                       {0, 32},
+	"time_low":                  {0, 8},
+	"time_mid":                  {8, 12},
+	"time_hi_and_version":       {12, 16},
+	"version":                   {12, 13},
+	"time_hi":                   {13, 16},
+	"clock_seq":                 {16, 20},
+	"clock_seq_hi_and_reserved": {16, 18},
+	"clock_seq_low":             {18, 20},
+	"node":                      {20, 32},
+}
+
+// **********************************************  Template
+
+// A Template is a compiled printing pattern built from named fields.
+//
+// Fields are written in braces and print as hex digits:
+//
+//	{hex}                        all 32 digits
+//	{time_low}                   8 digits
+//	{time_mid}                   4 digits
+//	{time_hi_and_version}        4 digits
+//	{version}                    1 digit
+//	{time_hi}                    the 3 digits after the version
+//	{clock_seq}                  4 digits
+//	{clock_seq_hi_and_reserved}  2 digits
+//	{clock_seq_low}              2 digits
+//	{node}                       12 digits
+//
+// A field prints in lower case or, when written as {node:X}, in upper
+// case. Any other text such as separators or a urn:uuid: prefix is
+// printed as is; {{ and }} print literal braces.
+//
+// Every digit of the UUID must be printed exactly once so that Parse
+// can read back anything the Template prints.
+type Template struct {
+	source string
+	parts  []templatePart
+}
+
+// Either literal text or a range of nibbles
+type templatePart struct {
+	text       string
+	start, end int
+	upper      bool
+}
+
+// NewTemplate compiles a Template.
+// Returns an error if a field is unknown, braces are unbalanced or
+// the fields do not cover the UUID exactly once.
+func NewTemplate(pTemplate string) (*Template, error) {
+	o := &Template{source: pTemplate}
+	var covered [length * 2]bool
+	var text []byte
+	for i := 0; i < len(pTemplate); i++ {
+		c := pTemplate[i]
+		switch {
+		case c == '{' && strings.HasPrefix(pTemplate[i:], "{{"),
+			c == '}' && strings.HasPrefix(pTemplate[i:], "}}"):
+			text = append(text, c)
+			i++
+			continue
+		case c == '}':
+			return nil, errors.New("uuid.NewTemplate: unmatched }")
+		case c != '{':
+			text = append(text, c)
+			continue
+		}
+		end := strings.IndexByte(pTemplate[i:], '}')
+		if end < 0 {
+			return nil, errors.New("uuid.NewTemplate: unmatched {")
+		}
+		name, upper := pTemplate[i+1:i+end], false
+		if j := strings.IndexByte(name, ':'); j >= 0 {
+			switch name[j+1:] {
+			case "x":
+			case "X":
+				upper = true
+			default:
+				return nil, errors.New("uuid.NewTemplate: invalid case in {" + name + "}")
+			}
+			name = name[:j]
+		}
+		field, ok := templateFields[name]
+		if !ok {
+			return nil, errors.New("uuid.NewTemplate: unknown field {" + name + "}")
+		}
+		for n := field[0]; n < field[1]; n++ {
+			if covered[n] {
+				return nil, errors.New("uuid.NewTemplate: field {" + name + "} overlaps another field")
+			}
+			covered[n] = true
+		}
+		if len(text) > 0 {
+			o.parts = append(o.parts, templatePart{text: string(text)})
+			text = text[:0]
+		}
+		o.parts = append(o.parts, templatePart{start: field[0], end: field[1], upper: upper})
+		i += end
+	}
+	if len(text) > 0 {
+		o.parts = append(o.parts, templatePart{text: string(text)})
+	}
+	for _, c := range covered {
+		if !c {
+			return nil, errors.New("uuid.NewTemplate: fields do not cover the whole UUID")
+		}
+	}
+	return o, nil
+}
+
+// String returns the source of the Template.
+func (o *Template) String() string {
+	return o.source
+}
+
+// Format prints the UUID using the Template.
+func (o *Template) Format(pUUID UUID) string {
+	return string(o.Append(nil, pUUID))
+}
+
+// Append appends the UUID printed using the Template to dst and
+// returns the extended buffer.
+func (o *Template) Append(dst []byte, pUUID UUID) []byte {
+	b := pUUID.Bytes()
+	for _, p := range o.parts {
+		if p.start == p.end {
+			dst = append(dst, p.text...)
+			continue
+		}
+		digits := lowerHex
+		if p.upper {
+			digits = upperHex
+		}
+		for n := p.start; n < p.end; n++ {
+			dst = append(dst, digits[nibble(b, n)])
+		}
+	}
+	return dst
+}
+
+// Parse creates a UUID from a string printed by the Template.
+// Hex digits are accepted in either case.
+func (o *Template) Parse(pUUID string) (UUID, error) {
+	u := new(Array)
+	s := pUUID
+	for _, p := range o.parts {
+		if p.start == p.end {
+			if !strings.HasPrefix(s, p.text) {
+				return nil, errors.New("uuid.Template.Parse: expected " + p.text)
+			}
+			s = s[len(p.text):]
+			continue
+		}
+		if len(s) < p.end-p.start {
+			return nil, errors.New("uuid.Template.Parse: string too short")
+		}
+		for n := p.start; n < p.end; n++ {
+			v, ok := fromHex(s[0])
+			if !ok {
+				return nil, errors.New("uuid.Template.Parse: invalid hex digit")
+			}
+			u[n/2] |= v << (4 * uint(1-n%2))
+			s = s[1:]
+		}
+	}
+	if s != "" {
+		return nil, errors.New("uuid.Template.Parse: string too long")
+	}
+	return u, nil
+}
+
+// ***************************************************  Helpers
+
+// Gets the nth nibble, the high nibble of each byte first
+func nibble(pData []byte, n int) byte {
+	if n%2 == 0 {
+		return pData[n/2] >> 4
+	}
+	return pData[n/2] & 0x0F
+}
+
+func fromHex(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
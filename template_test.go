@@ -0,0 +1,87 @@
+package uuid
+
+/****************
+ * Date: 19/10/26
+ * Time: 12:47 AM
+ ***************/
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUUID_NewTemplate(t *testing.T) {
+	u, _ := Parse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	for _, v := range []struct {
+		template, expected string
+	}{
+		{"{hex}", "6ba7b8109dad11d180b400c04fd430c8"},
+		{"urn:uuid:{time_low}-{time_mid}-{time_hi_and_version}-{clock_seq}-{node}", "urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{"{{{time_low:X}-{time_mid:X}-{time_hi_and_version}-{clock_seq_hi_and_reserved:X}{clock_seq_low:X}-{node}}}", "{6BA7B810-9DAD-11d1-80B4-00c04fd430c8}"},
+		{"v{version}:{node:X}/{clock_seq_low}{clock_seq_hi_and_reserved}/{time_hi}.{time_mid}.{time_low}", "v1:00C04FD430C8/b480/1d1.9dad.6ba7b810"},
+	} {
+		o, err := NewTemplate(v.template)
+		if err != nil {
+			t.Errorf("Expected %s to compile but got: %s", v.template, err)
+			continue
+		}
+		if o.String() != v.template {
+			t.Errorf("Expected the template source but got %s", o)
+		}
+		s := o.Format(u)
+		if s != v.expected {
+			t.Errorf("Expected %s to print %s but got %s", v.template, v.expected, s)
+		}
+		for _, id := range []UUID{u, NewV4(), NewV1(), NewV7()} {
+			u2, err := o.Parse(o.Format(id))
+			if err != nil || !Equal(id, u2) {
+				t.Errorf("Expected %s to read back %s but got %v %v", v.template, id, u2, err)
+			}
+		}
+		u2, err := o.Parse(strings.ToUpper(s))
+		if strings.HasPrefix(v.template, "{") && (err != nil || !Equal(u, u2)) {
+			t.Errorf("Expected %s to read back hex in either case but got %v %v", v.template, u2, err)
+		}
+	}
+}
+
+func TestUUID_NewTemplate_invalid(t *testing.T) {
+	for _, v := range []string{
+		"",
+		"{time_low}-{time_mid}-{time_hi_and_version}-{clock_seq}",
+		"{hex}{node}",
+		"{time_low}-{time_mid}-{time_hi_and_version}-{clock_seq}-{node}-{version}",
+		"{foo}",
+		"{hex",
+		"{hex}}",
+		"}{hex}",
+		"{hex:Y}",
+	} {
+		if _, err := NewTemplate(v); err == nil {
+			t.Error("Expected error due to invalid template:", v)
+		}
+	}
+}
+
+func TestUUID_Template_Parse(t *testing.T) {
+	o, err := NewTemplate("urn:uuid:{time_low}-{time_mid}-{time_hi_and_version}-{clock_seq}-{node}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range invalidHexStrings {
+		if _, err := o.Parse(v); err == nil {
+			t.Error("Expected error due to invalid UUID string:", v)
+		}
+	}
+	for _, v := range []string{
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		"urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c",
+		"urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8a",
+		"urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430cg",
+		"urn:uuid:6ba7b8109-dad-11d1-80b4-00c04fd430c8",
+	} {
+		if _, err := o.Parse(v); err == nil {
+			t.Error("Expected error due to invalid UUID string:", v)
+		}
+	}
+}
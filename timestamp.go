@@ -6,6 +6,7 @@ package uuid
  ***************/
 
 import (
+	"sync"
 	"time"
 )
 
@@ -16,14 +17,18 @@ const (
 	// Difference between
 	gregorianToUNIXOffset uint64 = 0x01B21DD213814000
 
-	// set the following to the number of 100ns ticks of the actual
-	// resolution of your system's clock
-	idsPerTimestamp = 1024
+	// The fewest ticks UUIDs may run ahead of the clock
+	minIdsPerTimestamp = 1024
+
+	// The number of clock ticks to measure the resolution over
+	resolutionSamples = 4
 )
 
 var (
-	lastTimestamp    Timestamp
-	idsThisTimestamp = idsPerTimestamp
+	// The measured resolution of the system clock, measured once when
+	// first needed rather than when the package is loaded
+	clockResolution     time.Duration
+	clockResolutionOnce sync.Once
 )
 
 // **********************************************  Timestamp
@@ -55,27 +60,87 @@ func (o Timestamp) Unix() time.Time {
 	return time.Unix(0, int64(t*100))
 }
 
+// ClockResolution returns the resolution of the system clock. It is
+// measured over a few clock ticks on the first call, which the first
+// v1 or v6 UUID makes.
+func ClockResolution() time.Duration {
+	clockResolutionOnce.Do(func() {
+		clockResolution = measureClockResolution()
+	})
+	return clockResolution
+}
+
+// Measures the smallest step of the clock over a few ticks
+func measureClockResolution() time.Duration {
+	resolution := time.Duration(0)
+	last := time.Now()
+	for i := 0; i < resolutionSamples; i++ {
+		now := time.Now()
+		for now.Equal(last) {
+			now = time.Now()
+		}
+		if d := now.Sub(last); resolution == 0 || d < resolution {
+			resolution = d
+		}
+		last = now
+	}
+	return resolution
+}
+
+// The number of 100ns ticks UUIDs may run ahead of the clock: a whole
+// clock reading but at least minIdsPerTimestamp
+func idsPerTimestampFor(pResolution time.Duration) Timestamp {
+	ids := Timestamp(pResolution / 100)
+	if ids < minIdsPerTimestamp {
+		ids = minIdsPerTimestamp
+	}
+	return ids
+}
+
 // Get time as 60-bit 100ns ticks since UUID epoch.
 // Compensate for the fact that real clock resolution is
 // less than 100ns.
-func currentUUIDTimestamp() Timestamp {
-	var timeNow Timestamp
+//
+// Each UUID takes the tick after the last one until the clock moves
+// past it, borrowing up to idsPerTimestamp ticks ahead of the clock.
+// Past that the generator sleeps until the clock catches up rather
+// than spinning, and unlocks the state while it sleeps. A clock which
+// reads earlier than before is returned as is, for clock to apply the
+// ClockPolicy. The state must be locked.
+func (o *State) currentUUIDTimestamp() Timestamp {
+	if o.idsPerTimestamp == 0 {
+		o.Unlock()
+		ids := idsPerTimestampFor(ClockResolution())
+		o.Lock()
+		o.idsPerTimestamp = ids
+	}
 	for {
-		timeNow = timestamp()
-
-		// if clock reading changed since last UUID generated
-		if lastTimestamp != timeNow {
-			// reset count of UUIDs with this timestamp
-			idsThisTimestamp = 0
-			lastTimestamp = timeNow
-			break
+		now := timestamp()
+		if now < o.clockReading {
+			o.clockReading, o.lastTimestamp = now, now
+			return now
 		}
-		if idsThisTimestamp < idsPerTimestamp {
-			idsThisTimestamp++
-			break
+		o.clockReading = now
+		if now > o.lastTimestamp {
+			o.lastTimestamp = now
+			return now
 		}
-		// going too fast for the clock; spin
+		if o.lastTimestamp+1 < now+o.idsPerTimestamp {
+			o.lastTimestamp++
+			return o.lastTimestamp
+		}
+		// going too fast for the clock; sleep
+		if o.observer != nil {
+			o.observer.ClockSpin()
+		}
+		// a tick is 100 nano seconds
+		o.sleep(time.Duration(o.lastTimestamp+1-now-o.idsPerTimestamp+1) * 100)
 	}
-	// add the count of UUIDs to low order bits of the clock reading
-	return timeNow + Timestamp(idsThisTimestamp)
+}
+
+// Sleeps with the state unlocked so that other goroutines can use it
+func (o *State) sleep(pDuration time.Duration) {
+	o.Unlock()
+	defer o.Lock()
+	time.Sleep(pDuration)
 }
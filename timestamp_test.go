@@ -7,6 +7,7 @@ package uuid
 
 import (
 	"testing"
+	"time"
 )
 
 func TestUUID_Timestamp_now(t *testing.T) {
@@ -18,3 +19,89 @@ func TestUUID_Timestamp_now(t *testing.T) {
 		t.Error("Expected a value")
 	}
 }
+
+func TestUUID_ClockResolution(t *testing.T) {
+	if r := ClockResolution(); r <= 0 || r > time.Second {
+		t.Errorf("Expected a measured clock resolution but got %v", r)
+	}
+	if ids := idsPerTimestampFor(100 * time.Nanosecond); ids != minIdsPerTimestamp {
+		t.Errorf("Expected at least %d ids per timestamp but got %d", minIdsPerTimestamp, ids)
+	}
+	if ids := idsPerTimestampFor(15625 * time.Microsecond); ids != 156250 {
+		t.Errorf("Expected a coarse clock to cover a whole reading but got %d", ids)
+	}
+}
+
+func TestUUID_State_currentUUIDTimestamp(t *testing.T) {
+	observer := new(countingObserver)
+	s := new(State)
+	s.observer = observer
+	s.idsPerTimestamp = 4
+	s.Lock()
+	defer s.Unlock()
+
+	last := Timestamp(0)
+	for i := 0; i < 10000; i++ {
+		now := s.currentUUIDTimestamp()
+		if now <= last {
+			t.Fatalf("Expected timestamps to increase but got %d after %d", now, last)
+		}
+		if now >= timestamp()+s.idsPerTimestamp {
+			t.Fatalf("Expected timestamps no more than %d ticks ahead of the clock", s.idsPerTimestamp)
+		}
+		last = now
+	}
+
+	// too far ahead of the clock
+	ahead := timestamp() + 1000
+	s.lastTimestamp = ahead
+	if now := s.currentUUIDTimestamp(); now <= ahead || observer.spins != 1 {
+		t.Errorf("Expected the generator to sleep when it runs ahead of the clock but got %d spins", observer.spins)
+	}
+
+	// a clock which moves backwards is returned as read
+	last = timestamp() + ticksPerSecond
+	s.clockReading, s.lastTimestamp = last, last
+	if now := s.currentUUIDTimestamp(); now >= last || s.lastTimestamp != now {
+		t.Errorf("Expected a backward clock to be returned but got %d after %d", now, last)
+	}
+}
+
+func TestUUID_State_currentUUIDTimestamp_resolution(t *testing.T) {
+	s := new(State)
+	s.Lock()
+	defer s.Unlock()
+	s.currentUUIDTimestamp()
+	if s.idsPerTimestamp != idsPerTimestampFor(ClockResolution()) {
+		t.Errorf("Expected the ids per timestamp to be set from the clock resolution but got %d", s.idsPerTimestamp)
+	}
+}
+
+func TestUUID_State_currentUUIDTimestamp_unlocked(t *testing.T) {
+	s := new(State)
+	s.idsPerTimestamp = minIdsPerTimestamp
+	s.Lock()
+	s.lastTimestamp = timestamp() + 200*ticksPerSecond/1000
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer s.Unlock()
+		s.currentUUIDTimestamp()
+	}()
+
+	// the state can be locked while the generator sleeps
+	time.Sleep(10 * time.Millisecond)
+	locked := make(chan struct{})
+	go func() {
+		s.Lock()
+		s.Unlock()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Expected the state to be unlocked while the generator sleeps")
+	}
+	<-done
+}
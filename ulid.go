@@ -0,0 +1,242 @@
+package uuid
+
+/****************
+ * Date: 18/10/26
+ * Time: 9:20 PM
+ ***************/
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// Crockford's base32 alphabet as used by the ULID specification
+	ulidAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	// A ULID is 128 bits encoded as 26 base32 characters
+	ulidLength = 26
+)
+
+var (
+	ulidDecoding [256]byte
+
+	ulidGenerator = NewULIDGenerator(false)
+	v7Generator   = NewULIDGenerator(true)
+)
+
+func init() {
+	for i := range ulidDecoding {
+		ulidDecoding[i] = 0xFF
+	}
+	for i := 0; i < len(ulidAlphabet); i++ {
+		ulidDecoding[ulidAlphabet[i]] = byte(i)
+		// ULIDs are case insensitive
+		ulidDecoding[ulidAlphabet[i]|0x20] = byte(i)
+	}
+}
+
+// NewULID will generate a new monotonic ULID held in a UUID.
+// The version and variant bits are not set.
+func NewULID() UUID {
+	return ulidGenerator.New()
+}
+
+// NewV7 will generate a new RFC9562 version 7 UUID
+// V7 is a unix millisecond timestamp followed by monotonic random
+// bits. It is also a valid ULID.
+func NewV7() UUID {
+	return v7Generator.New()
+}
+
+// FromULID creates a UUID from the Crockford base32 string
+// representation of a ULID. The 128 bits are copied verbatim.
+func FromULID(pULID string) (UUID, error) {
+	if len(pULID) != ulidLength {
+		return nil, errors.New("uuid.FromULID: invalid length")
+	}
+	// The first character only holds 3 bits
+	if ulidDecoding[pULID[0]] > 7 {
+		return nil, errors.New("uuid.FromULID: value overflows 128 bits")
+	}
+	var hi, lo uint64
+	for i := 0; i < ulidLength; i++ {
+		v := ulidDecoding[pULID[i]]
+		if v == 0xFF {
+			return nil, errors.New("uuid.FromULID: invalid character")
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+	o := new(Array)
+	binary.BigEndian.PutUint64(o[0:8], hi)
+	binary.BigEndian.PutUint64(o[8:16], lo)
+	return o, nil
+}
+
+// ToULID formats the 128 bits of a UUID as a ULID string.
+func ToULID(pUUID UUID) string {
+	b := pUUID.Bytes()
+	hi, lo := binary.BigEndian.Uint64(b[0:8]), binary.BigEndian.Uint64(b[8:16])
+	s := make([]byte, ulidLength)
+	for i := ulidLength - 1; i >= 0; i-- {
+		s[i] = ulidAlphabet[lo&0x1F]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(s)
+}
+
+// ******************************************************  ULIDGenerator
+
+// ULIDGenerator creates monotonic ULIDs.
+// When two ULIDs are created within the same millisecond the random
+// bits of the previous ULID are incremented rather than drawn again,
+// so ULIDs from one generator always sort in creation order.
+type ULIDGenerator struct {
+	sync.Mutex
+
+	// Whether to stamp RFC9562 version 7 and variant bits
+	v7 bool
+
+	// The unix millisecond of the last ULID
+	past uint64
+
+	// The random bits of the last ULID
+	// 80 bits for a ULID or 74 bits for a v7 UUID
+	hi uint16
+	lo uint64
+
+	// The leading random bits are fixed to prefix so that generators
+	// with different prefixes never collide
+	prefix     uint16
+	prefixBits uint
+}
+
+// NewULIDGenerator creates a monotonic generator.
+// If pV7 is true the version and variant bits are set so that the
+// results are also RFC9562 version 7 UUIDs.
+func NewULIDGenerator(pV7 bool) *ULIDGenerator {
+	return &ULIDGenerator{v7: pV7}
+}
+
+// New creates the next ULID.
+func (o *ULIDGenerator) New() UUID {
+	o.Lock()
+	defer o.Unlock()
+	if err := o.next(); err != nil {
+		panic(err)
+	}
+	return o.format(new(Array))
+}
+
+// Fill fills dst with the next ULIDs in order.
+// The generator is locked once and the random bits are read once for
+// the whole batch, then incremented for each ULID even when the
+// millisecond moves on. Returns an error if random data cannot be read.
+func (o *ULIDGenerator) Fill(dst []UUID) error {
+	if len(dst) == 0 {
+		return nil
+	}
+	arrays := make([]Array, len(dst))
+	o.Lock()
+	defer o.Unlock()
+	if err := o.next(); err != nil {
+		return err
+	}
+	dst[0] = o.format(&arrays[0])
+	for i := 1; i < len(arrays); i++ {
+		if now := unixMilli(); now > o.past {
+			o.past = now
+		}
+		if err := o.step(); err != nil {
+			return err
+		}
+		dst[i] = o.format(&arrays[i])
+	}
+	return nil
+}
+
+// Moves on to the next timestamp and random bits
+func (o *ULIDGenerator) next() error {
+	if now := unixMilli(); now > o.past {
+		o.past = now
+		return o.random()
+	}
+	return o.step()
+}
+
+// Increments the random bits within the same millisecond
+func (o *ULIDGenerator) step() error {
+	if !o.increment() {
+		// The random bits overflowed so borrow the next millisecond
+		o.past++
+		return o.random()
+	}
+	return nil
+}
+
+func unixMilli() uint64 {
+	return uint64(time.Now().UnixNano() / int64(time.Millisecond))
+}
+
+// the number of high random bits
+func (o *ULIDGenerator) hiBits() uint {
+	if o.v7 {
+		return 10
+	}
+	return 16
+}
+
+// the mask for the high random bits which are not the prefix
+func (o *ULIDGenerator) hiMask() uint16 {
+	return uint16(1<<(o.hiBits()-o.prefixBits) - 1)
+}
+
+func (o *ULIDGenerator) random() error {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	o.hi = (uint16(b[0])<<8|uint16(b[1]))&o.hiMask() | o.prefix<<(o.hiBits()-o.prefixBits)
+	o.lo = binary.BigEndian.Uint64(b[2:10])
+	return nil
+}
+
+// increments the random bits and reports false on overflow
+func (o *ULIDGenerator) increment() bool {
+	o.lo++
+	if o.lo != 0 {
+		return true
+	}
+	free := uint32(o.hi&o.hiMask()) + 1
+	o.hi = o.hi&^o.hiMask() | uint16(free)&o.hiMask()
+	return free <= uint32(o.hiMask())
+}
+
+// Lays out the timestamp and random bits
+func (o *ULIDGenerator) format(u *Array) UUID {
+	u[0] = byte(o.past >> 40)
+	u[1] = byte(o.past >> 32)
+	u[2] = byte(o.past >> 24)
+	u[3] = byte(o.past >> 16)
+	u[4] = byte(o.past >> 8)
+	u[5] = byte(o.past)
+	if !o.v7 {
+		u[6] = byte(o.hi >> 8)
+		u[7] = byte(o.hi)
+		binary.BigEndian.PutUint64(u[8:16], o.lo)
+		return u
+	}
+	// 12 bits of rand_a followed by 62 bits of rand_b
+	a := o.hi<<2 | uint16(o.lo>>62)
+	u[6] = byte(a >> 8)
+	u[7] = byte(a)
+	binary.BigEndian.PutUint64(u[8:16], o.lo&0x3FFFFFFFFFFFFFFF)
+	u.setVersion(7)
+	u.setRFC4122Variant()
+	return u
+}
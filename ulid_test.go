@@ -0,0 +1,107 @@
+package uuid
+
+/****************
+ * Date: 18/10/26
+ * Time: 9:48 PM
+ ***************/
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const (
+	ulidString = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	ulidHex    = "01563e3ab5d3d6764c61efb99302bd5b"
+)
+
+func TestUUID_FromULID(t *testing.T) {
+	u, err := FromULID(ulidString)
+	if err != nil {
+		t.Fatal("Expected a valid ULID but got error:", err)
+	}
+	if !Equal(u, NewHex(ulidHex)) {
+		t.Errorf("Expected %s but got %x", ulidHex, u.Bytes())
+	}
+	l, err := FromULID(strings.ToLower(ulidString))
+	if err != nil || !Equal(u, l) {
+		t.Error("Expected ULID decoding to be case insensitive", err)
+	}
+	for _, v := range []string{
+		"",
+		"01ARZ3NDEKTSV4RRFFQ69G5FA",
+		"01ARZ3NDEKTSV4RRFFQ69G5FAVV",
+		"01ARZ3NDEKTSV4RRFFQ69G5FAU",
+		"81ARZ3NDEKTSV4RRFFQ69G5FAV",
+	} {
+		if _, err := FromULID(v); err == nil {
+			t.Error("Expected error due to invalid ULID:", v)
+		}
+	}
+}
+
+func TestUUID_ToULID(t *testing.T) {
+	if s := ToULID(NewHex(ulidHex)); s != ulidString {
+		t.Errorf("Expected %s but got %s", ulidString, s)
+	}
+	for i := 0; i < 1000; i++ {
+		u := NewV4()
+		u2, err := FromULID(ToULID(u))
+		if err != nil || !Equal(u, u2) {
+			t.Errorf("Expected %s to survive a ULID round trip but got %s", u, u2)
+		}
+	}
+}
+
+func TestUUID_NewULID(t *testing.T) {
+	last := NewULID()
+	for i := 0; i < generate; i++ {
+		u := NewULID()
+		if bytes.Compare(last.Bytes(), u.Bytes()) >= 0 {
+			t.Fatalf("Expected monotonic ULIDs but got %s after %s", ToULID(u), ToULID(last))
+		}
+		last = u
+	}
+}
+
+func TestUUID_NewV7(t *testing.T) {
+	u := NewV7()
+	if u.Version() != 7 {
+		t.Errorf("Expected correct version %d, but got %d", 7, u.Version())
+	}
+	if u.Variant() != ReservedRFC4122 {
+		t.Errorf("Expected RFC4122 variant %x, but got %x", ReservedRFC4122, u.Variant())
+	}
+	if !parseUUIDRegex.MatchString(u.String()) {
+		t.Errorf("Expected string representation to be valid, given: %s", u.String())
+	}
+	last := u
+	for i := 0; i < generate; i++ {
+		u = NewV7()
+		if bytes.Compare(last.Bytes(), u.Bytes()) >= 0 {
+			t.Fatalf("Expected monotonic V7 UUIDs but got %s after %s", u, last)
+		}
+		if u.Version() != 7 || u.Variant() != ReservedRFC4122 {
+			t.Fatalf("Expected version and variant bits to survive increments: %s", u)
+		}
+		last = u
+	}
+}
+
+func TestUUID_ULIDGenerator_overflow(t *testing.T) {
+	for _, v7 := range []bool{false, true} {
+		g := NewULIDGenerator(v7)
+		u := g.New()
+		g.hi = g.hiMask()
+		g.lo = ^uint64(0)
+		past := g.past
+		u2 := g.New()
+		if g.past <= past {
+			t.Error("Expected the generator to borrow the next millisecond on overflow")
+		}
+		if bytes.Compare(u.Bytes(), u2.Bytes()) >= 0 {
+			t.Errorf("Expected monotonic ULIDs but got %s after %s", ToULID(u2), ToULID(u))
+		}
+	}
+}
@@ -66,7 +66,7 @@ func Example() {
 		fmt.Printf("Will never happen")
 	}
 
-	fmt.Printf(uuid.Formatter(u5, uuid.CurlyHyphen))
+	fmt.Print(uuid.Formatter(u5, uuid.CurlyHyphen))
 
 	uuid.SwitchFormat(uuid.BracketHyphen)
 }
@@ -109,7 +109,7 @@ func ExampleSetupFileSystemStateSaver() {
 
 func ExampleFormatter() {
 	u4 := uuid.NewV4()
-	fmt.Printf(uuid.Formatter(u4, uuid.CurlyHyphen))
+	fmt.Print(uuid.Formatter(u4, uuid.CurlyHyphen))
 }
 
 func ExampleSwitchFormat() {
@@ -3,6 +3,11 @@
 // NewV1, NewV3, NewV4, NewV5, for generating versions 1, 3, 4
 // and 5 UUIDs as specified in RFC-4122.
 //
+// NewV6 and NewV7 for generating versions 6 and 7 UUIDs as specified
+// in RFC-9562 and NewV8Hash for version 8 name-based UUIDs using
+// SHA-256 or another hash, or NewV8HMAC for keyed ones.
+// NewULID, FromULID and ToULID for interoperability with ULIDs.
+//
 // New([]byte), unsafe; NewHex(string); and Parse(string) for
 // creating UUIDs from existing data.
 //
@@ -23,12 +28,17 @@ package uuid
 
 import (
 	"encoding"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"hash"
+	"io"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 	"bytes"
 )
 
@@ -47,12 +57,26 @@ const (
 	// or closing bracket or any of the hyphens are optional.
 	// It is only used to extract the main bytes to create a UUID,
 	// so these imperfections are of no consequence.
-	hexPattern = `^(urn\:uuid\:)?[\{(\[]?([A-Fa-f0-9]{8})-?([A-Fa-f0-9]{4})-?([1-5][A-Fa-f0-9]{3})-?([A-Fa-f0-9]{4})-?([A-Fa-f0-9]{12})[\]\})]?$`
+	hexPattern = `^(urn\:uuid\:)?[\{(\[]?([A-Fa-f0-9]{8})-?([A-Fa-f0-9]{4})-?([1-8][A-Fa-f0-9]{3})-?([A-Fa-f0-9]{4})-?([A-Fa-f0-9]{12})[\]\})]?$`
 )
 
 var (
 	parseUUIDRegex = regexp.MustCompile(hexPattern)
-	format         string
+
+	// Holds the default compiled printing format
+	// Stored atomically so that the format can be switched while
+	// other goroutines print UUIDs
+	format atomic.Value
+
+	// The built in formats used to print with the fmt package,
+	// compiled once
+	cleanFormat       = compileFormat(string(Clean))
+	cleanHyphenFormat = compileFormat(string(CleanHyphen))
+
+	// The built in formats and their upper case forms by pattern,
+	// compiled once. Other patterns are compiled when used so that
+	// patterns built at run time are not kept.
+	builtInFormats = compileFormats(Clean, Curly, Bracket, CleanHyphen, CurlyHyphen, BracketHyphen, GoIdFormat)
 )
 
 func init() {
@@ -168,6 +192,10 @@ func (o Name) String() string {
 }
 
 // NewName will create a unique name from several sources
+// The sources are joined without separators so ("ab", "c") and
+// ("a", "bc") give the same name.
+//
+// Deprecated: Use StructuredName which cannot be ambiguous.
 func NewName(salt string, pNames ...UniqueName) UniqueName {
 	var s string
 	for _, s2 := range pNames {
@@ -209,18 +237,20 @@ const (
 )
 
 // Gets the current default format pattern
+// Safe for concurrent use with SwitchFormat
 func GetFormat() string {
-	return format
+	return currentFormat().pattern
 }
 
 // Switches the default printing format for ALL UUID strings
 // A valid format will have 6 groups if the supplied Format does not
+// Safe for concurrent use with String and GetFormat
 func SwitchFormat(pFormat Format) {
 	form := string(pFormat)
 	if strings.Count(form, "%") != 6 {
 		panic(errors.New("uuid.switchFormat: invalid formatting"))
 	}
-	format = form
+	format.Store(compiledFormat(form))
 }
 
 // Same as SwitchFormat but will make it uppercase
@@ -242,7 +272,55 @@ func Formatter(pUUID UUID, pFormat Format) string {
 	if strings.Count(form, "%") != 6 {
 		panic(errors.New("uuid.Formatter: invalid formatting"))
 	}
-	return formatter(pUUID, form)
+	return formatter(pUUID, compiledFormat(form))
+}
+
+// FormatVerb prints a UUID for the fmt package and lets each
+// UUID type implement fmt.Formatter. The verbs are independent of
+// the format set by SwitchFormat except %s:
+//		%s	the String method
+//		%v	the canonical CleanHyphen form
+//		%+v	the canonical form with the version, variant and time
+//		%#v	Go syntax
+//		%q	the canonical form quoted
+//		%x	the Clean form
+//		%X	the Clean form in upper case
+// Width and the '-' flag pad the output as they do for strings.
+func FormatVerb(pUUID UUID, pFmt fmt.State, pVerb rune) {
+	var s string
+	switch pVerb {
+	case 's':
+		s = pUUID.String()
+	case 'v':
+		s = formatter(pUUID, cleanHyphenFormat)
+		if pFmt.Flag('#') {
+			s = fmt.Sprintf("uuid.NewHex(%q)", formatter(pUUID, cleanFormat))
+		} else if pFmt.Flag('+') {
+			s = fmt.Sprintf("%s (version %d, variant %x", s, pUUID.Version(), pUUID.Variant())
+			if t, ok := timeOf(pUUID); ok {
+				s += ", time " + t.UTC().Format(time.RFC3339Nano)
+			}
+			s += ")"
+		}
+	case 'q':
+		s = strconv.Quote(formatter(pUUID, cleanHyphenFormat))
+	case 'x':
+		s = formatter(pUUID, cleanFormat)
+	case 'X':
+		s = strings.ToUpper(formatter(pUUID, cleanFormat))
+	default:
+		fmt.Fprintf(pFmt, "%%!%c(uuid.UUID=%s)", pVerb, formatter(pUUID, cleanHyphenFormat))
+		return
+	}
+	if w, ok := pFmt.Width(); ok && w > len(s) {
+		pad := strings.Repeat(" ", w-len(s))
+		if pFmt.Flag('-') {
+			s += pad
+		} else {
+			s = pad + s
+		}
+	}
+	io.WriteString(pFmt, s)
 }
 
 // **********************************************  UUID Versions
@@ -256,6 +334,9 @@ const (
 	RFC4122v3
 	RFC4122v4
 	RFC4122v5
+	RFC9562v6
+	RFC9562v7
+	RFC9562v8
 )
 
 // ***************************************************  Helpers
@@ -288,9 +369,63 @@ func setVariant(pByte *byte, pVariant byte) {
 	*pByte |= pVariant
 }
 
-// format a UUID into a human readable string
-func formatter(pUUID UUID, pFormat string) string {
+// Retrieves the time from time based UUIDs
+func timeOf(pUUID UUID) (time.Time, bool) {
+	if pUUID.Variant() != ReservedRFC4122 {
+		return time.Time{}, false
+	}
 	b := pUUID.Bytes()
-	return fmt.Sprintf(pFormat, b[0:4], b[4:6], b[6:8], b[8:9], b[9:10], b[10:pUUID.Size()])
+	switch pUUID.Version() {
+	case 1:
+		t := Timestamp(b[6]&0x0F)<<56 | Timestamp(b[7])<<48 |
+			Timestamp(b[4])<<40 | Timestamp(b[5])<<32 |
+			Timestamp(b[0])<<24 | Timestamp(b[1])<<16 | Timestamp(b[2])<<8 | Timestamp(b[3])
+		return t.Unix(), true
+	case 6:
+		t := Timestamp(binary.BigEndian.Uint32(b[0:4]))<<28 |
+			Timestamp(binary.BigEndian.Uint16(b[4:6]))<<12 |
+			Timestamp(binary.BigEndian.Uint16(b[6:8])&0x0FFF)
+		return t.Unix(), true
+	case 7:
+		ms := int64(binary.BigEndian.Uint64(b[0:8]) >> 16)
+		return time.Unix(ms/1000, (ms%1000)*int64(time.Millisecond)), true
+	}
+	return time.Time{}, false
+}
+
+// Gets the default compiled format
+func currentFormat() *printFormat {
+	return format.Load().(*printFormat)
+}
+
+// Compiles the formats and their upper case forms by pattern
+func compileFormats(pFormats ...Format) map[string]*printFormat {
+	formats := make(map[string]*printFormat)
+	for _, f := range pFormats {
+		for _, p := range []string{string(f), strings.ToUpper(string(f))} {
+			formats[p] = compileFormat(p)
+		}
+	}
+	return formats
+}
+
+// Gets the compiled format of a pattern
+// The default and built in formats are compiled already, any other
+// pattern is compiled for each use.
+func compiledFormat(pFormat string) *printFormat {
+	if o, ok := builtInFormats[pFormat]; ok {
+		return o
+	}
+	// the default is not set while the package is initialised
+	if o, ok := format.Load().(*printFormat); ok && o.pattern == pFormat {
+		return o
+	}
+	return compileFormat(pFormat)
+}
+
+// format a UUID into a human readable string
+func formatter(pUUID UUID, pFormat *printFormat) string {
+	var b [maxFormatLength]byte
+	return string(pFormat.append(b[:0], pUUID.Bytes()))
 }
 
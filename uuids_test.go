@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -179,6 +180,58 @@ func TestUUID_Formats_String(t *testing.T) {
 	}
 }
 
+// Run with -race to check that switching formats does not race
+// with printing
+func TestUUID_SwitchFormat_concurrent(t *testing.T) {
+	defer SwitchFormat(CleanHyphen)
+
+	ids := []UUID{NewV4(), NewV1()}
+	valid := make(map[string]bool)
+	for _, u := range ids {
+		for _, f := range formats {
+			valid[Formatter(u, f)] = true
+		}
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	errs := make(chan string, 1)
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				for _, u := range ids {
+					if s := u.String(); !valid[s] {
+						select {
+						case errs <- s:
+						default:
+						}
+					}
+				}
+			}
+		}()
+	}
+	for i := 0; i < 10000; i++ {
+		SwitchFormat(formats[i%len(formats)])
+		GetFormat()
+	}
+	close(done)
+	wg.Wait()
+
+	select {
+	case s := <-errs:
+		t.Error("Expected every String output to match a switched format but got", s)
+	default:
+	}
+}
+
 func TestUUID_Formatter(t *testing.T) {
 	ids := []UUID{NewV4(), NewV1()}
 
@@ -227,6 +280,36 @@ func TestUUID_Formatter(t *testing.T) {
 	}
 }
 
+func TestUUID_FormatVerb(t *testing.T) {
+	SwitchFormat(CurlyHyphen)
+	defer SwitchFormat(CleanHyphen)
+
+	u, _ := Parse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	u1 := NamespaceDNS
+	for _, v := range []struct {
+		format   string
+		u        interface{}
+		expected string
+	}{
+		{"%s", u, "{6ba7b810-9dad-11d1-80b4-00c04fd430c8}"},
+		{"%v", u, "6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{"%v", *u1, "6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{"%x", u, "6ba7b8109dad11d180b400c04fd430c8"},
+		{"%X", u1, "6BA7B8109DAD11D180B400C04FD430C8"},
+		{"%q", u, `"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`},
+		{"%#v", u, `uuid.NewHex("6ba7b8109dad11d180b400c04fd430c8")`},
+		{"%+v", u1, "6ba7b810-9dad-11d1-80b4-00c04fd430c8 (version 1, variant 80, time 1998-02-04T22:13:53.1511824Z)"},
+		{"%+v", NewV3(u, Name("test")), "45a113ac-c7f2-30b0-90a5-a399ab912716 (version 3, variant 80)"},
+		{"%40v|", u, "    6ba7b810-9dad-11d1-80b4-00c04fd430c8|"},
+		{"%-40x|", u, "6ba7b8109dad11d180b400c04fd430c8        |"},
+		{"%d", u, "%!d(uuid.UUID=6ba7b810-9dad-11d1-80b4-00c04fd430c8)"},
+	} {
+		if s := fmt.Sprintf(v.format, v.u); s != v.expected {
+			t.Errorf("Expected %s to print %s but got %s", v.format, v.expected, s)
+		}
+	}
+}
+
 func TestUUID_NewHex(t *testing.T) {
 	s := "f3593cffee9240df408687825b523f13"
 	u := NewHex(s)
@@ -444,6 +527,6 @@ func outputLn(a ...interface{}) {
 
 func outputF(format string, a ...interface{}) {
 	if printer {
-		fmt.Printf(format, a)
+		fmt.Printf(format, a...)
 	}
 }